@@ -3,14 +3,19 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strings"
 	"sync"
+	"syscall"
+	"time"
 
 	"mangarr/internal/domain"
 	"mangarr/internal/download"
 	"mangarr/internal/files"
 	"mangarr/internal/parse"
 	"mangarr/internal/sanitize"
+	"mangarr/internal/selection"
 	"mangarr/internal/source"
 	"mangarr/internal/templater"
 
@@ -21,7 +26,9 @@ var downloadCmd = &cobra.Command{
 	Use:   "download",
 	Short: "Download a specified chapter",
 	Run: func(cmd *cobra.Command, _ []string) {
-		ctx := cmd.Context()
+		// cancelled on interrupt to abort in-flight requests
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
 
 		if !cmd.Flags().Changed("first") && !cmd.Flags().Changed("chapters") {
 			latest = true
@@ -32,28 +39,22 @@ var downloadCmd = &cobra.Command{
 			return
 		}
 
-		var s domain.Source
-
-		switch mangaSource {
-		case "tcbscans":
-			s = source.NewTCBScans(manga)
-		case "mangadex":
-			s = source.NewMangadex(manga, group, language)
-		case "mangaplus":
-			s = source.NewMangaPlus(manga)
-		case "flamecomics":
-			s = source.NewFlamecomics(manga)
-		case "asurascans":
-			s = source.NewAsurascans(manga)
-		case "cubari":
-			s = source.NewCubari(manga, group)
-		default:
-			fmt.Println("Invalid source:", mangaSource)
+		loadCustomSources()
+
+		policy, err := selection.New(preferredGroups, selectionName)
+		if err != nil {
+			fmt.Println("Invalid selection:", err)
 			return
 		}
 
-		if err := s.ValidateInput(); err != nil {
-			fmt.Printf("Invalid input: %v\n", err)
+		s, err := source.New(mangaSource, source.Input{
+			Manga:    manga,
+			Group:    group,
+			Language: language,
+			Options:  options,
+		})
+		if err != nil {
+			fmt.Println("Invalid source:", err)
 			return
 		}
 
@@ -68,9 +69,9 @@ var downloadCmd = &cobra.Command{
 			return
 		}
 
-		var selectedChapterNumbers []float32
+		var selectedChapterNumbers []domain.ChapterNumber
 
-		firstChapterNr, latestChapterNr, err := parse.GetMinAndMaxKeys(selectedManga.Chapters)
+		firstChapterNr, latestChapterNr, err := parse.GetFirstAndLatestChapters(selectedManga)
 		if err != nil {
 			fmt.Printf("Failed to parse chapter number for %q: %v\n", selectedManga.Title, err)
 			return
@@ -82,7 +83,7 @@ var downloadCmd = &cobra.Command{
 		case latest:
 			selectedChapterNumbers = latestChapterNr
 		default:
-			selectedChapterNumbers, err = parse.ChapterSelection(chapterNumbers, selectedManga.Chapters)
+			selectedChapterNumbers, err = parse.ChapterSelection(chapterNumbers, selectedManga)
 			if err != nil {
 				fmt.Printf("Failed to parse chapter selection for %q: %v\n", selectedManga.Title, err)
 				return
@@ -102,14 +103,21 @@ var downloadCmd = &cobra.Command{
 			go func() {
 				defer wg.Done()
 
-				selectedChapter, ok := selectedManga.Chapters[num]
+				releases := selectedManga.AvailableReleases(num, time.Now())
+				if len(releases) == 0 && len(selectedManga.Chapters[num.Key()]) != 0 {
+					err := selectedManga.Chapters[num.Key()][0].CheckAvailable(time.Now())
+					fmt.Printf("Skipping unavailable chapter %s: %v\n", num, err)
+					return
+				}
+
+				selectedChapter, ok := policy.Select(releases)
 				if !ok {
-					fmt.Printf("Failed to find chapter with number: %g\n", num)
+					fmt.Printf("Failed to find chapter with number: %s\n", num)
 					return
 				}
 
 				if err := s.GetImageURLs(ctx, &selectedChapter); err != nil {
-					fmt.Printf("Failed to get image URLs for chapter %g: %v\n", selectedChapter.Number, err)
+					fmt.Printf("Failed to get image URLs for chapter %s: %v\n", selectedChapter.Number, err)
 					return
 				}
 
@@ -125,15 +133,36 @@ var downloadCmd = &cobra.Command{
 				}
 
 				fmt.Printf("Downloading %q...\n", templatedName)
-				if err := download.Chapter(ctx, contentPath, selectedChapter); err != nil {
+				result, err := download.Chapter(ctx, contentPath, selectedChapter)
+				if err != nil {
 					fmt.Printf("Failed to download chapter %q: %v\n", templatedName, err)
 					return
 				}
 
-				fmt.Printf("Finished downloading %q\n", templatedName)
+				fmt.Printf("Finished downloading %q (%s)\n", templatedName, describeResult(result))
 			}()
 		}
 
 		wg.Wait()
 	},
 }
+
+// describeResult summarizes the details of a downloaded chapter
+func describeResult(result download.Result) string {
+	details := []string{fmt.Sprintf("%d pages", result.Pages)}
+
+	if result.Chapter.Volume != "" {
+		details = append(details, "volume "+result.Chapter.Volume)
+	}
+	if result.Chapter.Group != "" {
+		details = append(details, "group "+result.Chapter.Group)
+	}
+	if result.Chapter.Language != "" {
+		details = append(details, "language "+result.Chapter.Language)
+	}
+	if !result.Chapter.PublishedAt.IsZero() {
+		details = append(details, "published "+result.Chapter.PublishedAt.Format(time.DateOnly))
+	}
+
+	return strings.Join(details, ", ")
+}
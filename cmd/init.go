@@ -1,18 +1,29 @@
 package cmd
 
+import (
+	"strings"
+
+	"mangarr/internal/source"
+)
+
 var (
 	configPath        string
 	naming            string
 	downloadDirectory string
 	mangaSource       string
+	searchSource      string
 
 	manga    string
 	group    string
 	language string
+	options  map[string]string
 
 	chapterNumbers string
 	first          bool
 	latest         bool
+
+	preferredGroups []string
+	selectionName   string
 )
 
 func initRootFlags() {
@@ -38,7 +49,7 @@ func initDownloadFlags() {
 		"source",
 		"s",
 		"",
-		"specifies the source of the manga",
+		"specifies the source of the manga, one of: "+strings.Join(source.Keys(), ", "),
 	)
 	downloadCmd.Flags().StringVarP(
 		&naming,
@@ -70,6 +81,26 @@ func initDownloadFlags() {
 		"en",
 		"specifies the language you want to download. default: en",
 	)
+	downloadCmd.Flags().StringToStringVarP(
+		&options,
+		"option",
+		"o",
+		nil,
+		"specifies a source specific option as key=value, can be repeated",
+	)
+
+	downloadCmd.Flags().StringSliceVar(
+		&preferredGroups,
+		"preferredGroups",
+		nil,
+		"specifies the groups whose releases you prefer, in order of preference",
+	)
+	downloadCmd.Flags().StringVar(
+		&selectionName,
+		"selection",
+		"newest",
+		"specifies how to choose between multiple releases of a chapter, one of: newest, pages, first",
+	)
 
 	downloadCmd.Flags().StringVarP(
 		&chapterNumbers,
@@ -101,3 +132,15 @@ func initDownloadFlags() {
 	_ = downloadCmd.MarkFlagRequired("source")
 	_ = downloadCmd.MarkFlagRequired("manga")
 }
+
+func initSearchFlags() {
+	searchCmd.Flags().StringVarP(
+		&searchSource,
+		"source",
+		"s",
+		"",
+		"specifies the source you want to search, one of: "+strings.Join(source.Keys(), ", "),
+	)
+
+	_ = searchCmd.MarkFlagRequired("source")
+}
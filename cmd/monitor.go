@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
@@ -17,17 +19,27 @@ import (
 	"mangarr/internal/logger"
 	"mangarr/internal/parse"
 	"mangarr/internal/sanitize"
+	"mangarr/internal/selection"
 	"mangarr/internal/source"
 	"mangarr/internal/templater"
 
+	"github.com/rs/zerolog"
 	"github.com/spf13/cobra"
 )
 
+// monitoredSource is a source together with the policy used to select between releases of a chapter
+type monitoredSource struct {
+	domain.Source
+	policy selection.Policy
+}
+
 var monitorCmd = &cobra.Command{
 	Use:   "monitor",
 	Short: "Monitor a specified manga for new chapters",
 	Run: func(cmd *cobra.Command, _ []string) {
-		ctx := cmd.Context()
+		// cancelled on shutdown to abort in-flight requests
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
 
 		// read config
 		cfg := config.New(configPath, buildinfo.Version)
@@ -46,30 +58,67 @@ var monitorCmd = &cobra.Command{
 			log.Fatal().Err(err).Msgf("invalid download location")
 		}
 
-		var sources []domain.Source
+		if err := source.RegisterScrapers(cfg.Config.Sources); err != nil {
+			log.Error().Err(err).Msg("error registering custom sources")
+		}
+
+		if err := source.RegisterThemes(cfg.Config.Themes); err != nil {
+			log.Error().Err(err).Msg("error registering theme sources")
+		}
+
+		if err := source.RegisterPlugins(cfg.Config.Plugins, log.With().Str("module", "plugin").Logger()); err != nil {
+			log.Error().Err(err).Msg("error registering plugins")
+		}
+
+		var sources []monitoredSource
 
 		for mangaName, monitoredManga := range cfg.Config.MonitoredManga {
-			switch monitoredManga.Source {
-			case "tcbscans":
-				sources = append(sources, source.NewTCBScans(monitoredManga.Manga))
-			case "mangadex":
-				sources = append(sources, source.NewMangadex(monitoredManga.Manga, monitoredManga.Group, monitoredManga.Language))
-			case "mangaplus":
-				sources = append(sources, source.NewMangaPlus(monitoredManga.Manga))
-			case "flamecomics":
-				sources = append(sources, source.NewFlamecomics(monitoredManga.Manga))
-			case "asurascans":
-				sources = append(sources, source.NewAsurascans(monitoredManga.Manga))
-			case "cubari":
-				sources = append(sources, source.NewCubari(monitoredManga.Manga, monitoredManga.Group))
-			default:
-				log.Error().Msgf("unknown monitored manga source for %s: %s", mangaName, monitoredManga.Source)
+			policy, err := selection.New(monitoredManga.PreferredGroups, monitoredManga.Selection)
+			if err != nil {
+				log.Error().Err(err).Msgf("error setting up monitored manga %s", mangaName)
+				continue
+			}
+
+			s, err := source.New(monitoredManga.Source, source.Input{
+				Manga:    monitoredManga.Manga,
+				Group:    monitoredManga.Group,
+				Language: monitoredManga.Language,
+				Options:  monitoredManga.Options,
+			})
+			if err != nil {
+				log.Error().Err(err).Msgf("error setting up monitored manga %s", mangaName)
 				continue
 			}
+
+			sources = append(sources, monitoredSource{Source: s, policy: policy})
 		}
 
 		log.Info().Msg("starting to monitor configured manga")
 
+		// sources that watch for new chapters themselves import all of them whenever they notice changes
+		var polled []monitoredSource
+		watchWg := sync.WaitGroup{}
+
+		for _, s := range sources {
+			watcher, ok := s.Source.(domain.Watcher)
+			if !ok {
+				polled = append(polled, s)
+				continue
+			}
+
+			watchWg.Add(1)
+
+			go func() {
+				defer watchWg.Done()
+
+				checkSource(ctx, cfg, log, s, true)
+
+				if err := watcher.Watch(ctx, func() { checkSource(ctx, cfg, log, s, true) }); err != nil {
+					log.Error().Err(err).Msgf("error watching %s", s)
+				}
+			}()
+		}
+
 		ticker := time.NewTicker(time.Duration(cfg.Config.CheckInterval)*time.Minute - 40*time.Second)
 		defer ticker.Stop()
 
@@ -82,74 +131,12 @@ var monitorCmd = &cobra.Command{
 				case <-quit:
 					return
 				case <-ticker.C:
-					for _, s := range sources {
+					for _, s := range polled {
 						wg.Add(1)
 
 						go func() {
 							defer wg.Done()
-
-							if err := s.ValidateInput(); err != nil {
-								log.Error().Err(err).Msgf("error validating input")
-								return
-							}
-
-							selectedManga, err := s.GetManga(ctx)
-							if err != nil {
-								log.Error().Err(err).Msgf("error getting manga from %s", s)
-								return
-							}
-							mLog := log.With().Str("manga", selectedManga.Title).Str("source", s.String()).Logger()
-
-							if err := s.GetChapters(ctx, selectedManga); err != nil {
-								mLog.Error().Err(err).Msg("error getting manga chapters")
-								return
-							}
-
-							_, latestChapterNr, err := parse.GetMinAndMaxKeys(selectedManga.Chapters)
-							if err != nil {
-								mLog.Error().Err(err).Msg("error parsing chapter number")
-								return
-							}
-
-							if len(latestChapterNr) == 0 {
-								mLog.Error().Msg("error finding latest chapter")
-								return
-							}
-
-							var num float32
-							for _, n := range latestChapterNr {
-								num = n
-								break
-							}
-
-							selectedChapter, ok := selectedManga.Chapters[num]
-							if !ok {
-								mLog.Error().Err(err).Msgf("error finding chapter with number %g", num)
-								return
-							}
-
-							if err := s.GetImageURLs(ctx, &selectedChapter); err != nil {
-								mLog.Error().Err(err).Msgf("error getting image urls for chapter %g", selectedChapter.Number)
-								return
-							}
-
-							t := templater.New(selectedManga, selectedChapter)
-							templatedName := t.ExecTemplate(cfg.Config.NamingTemplate)
-
-							chapterFolder := sanitize.Filename(templatedName)
-							contentPath := filepath.Join(cfg.Config.DownloadLocation, selectedManga.Title, chapterFolder+".cbz")
-
-							if _, err := os.Stat(contentPath); err == nil {
-								mLog.Debug().Msgf("chapter has already been downloaded, skipping %q", templatedName)
-								return
-							}
-
-							mLog.Info().Msgf("downloading %q", templatedName)
-							if err := download.Chapter(ctx, contentPath, selectedChapter); err != nil {
-								mLog.Error().Err(err).Msgf("error downloading chapter %q", templatedName)
-								return
-							}
-							mLog.Info().Msgf("finished downloading %q", templatedName)
+							checkSource(ctx, cfg, log, s, false)
 						}()
 					}
 
@@ -163,7 +150,112 @@ var monitorCmd = &cobra.Command{
 		signal.Notify(sigCh, syscall.SIGHUP, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGTERM)
 
 		fmt.Printf("received signal: %s, stopping monitoring.\n", <-sigCh)
+		cancel()
 		quit <- true
 		wg.Wait()
+		watchWg.Wait()
 	},
 }
+
+// checkSource downloads the latest available chapter of the manga of the source, or all of its chapters if all is set,
+// chapters that have already been downloaded or can't be read yet are skipped
+func checkSource(ctx context.Context, cfg *config.AppConfig, log logger.Logger, s monitoredSource, all bool) {
+	selectedManga, err := s.GetManga(ctx)
+	if err != nil {
+		log.Error().Err(err).Msgf("error getting manga from %s", s)
+		return
+	}
+	mLog := log.With().Str("manga", selectedManga.Title).Str("source", s.String()).Logger()
+
+	if err := s.GetChapters(ctx, selectedManga); err != nil {
+		mLog.Error().Err(err).Msg("error getting manga chapters")
+		return
+	}
+
+	_, latestChapterNr, err := parse.GetFirstAndLatestChapters(selectedManga)
+	if err != nil {
+		mLog.Error().Err(err).Msg("error parsing chapter number")
+		return
+	}
+
+	if len(latestChapterNr) == 0 {
+		mLog.Error().Msg("error finding latest chapter")
+		return
+	}
+
+	numbers := latestChapterNr
+	if all {
+		numbers = selectedManga.ChapterNumbers()
+	} else {
+		latest, ok := selectedManga.LatestAvailable(time.Now())
+		if !ok {
+			mLog.Debug().Msgf("no chapter is available yet, latest chapter is %s", latestChapterNr[0])
+			return
+		}
+
+		// locked chapters are downloaded once they become available
+		if latest.Key() != latestChapterNr[0].Key() {
+			mLog.Debug().Msgf("latest chapter %s is not available yet, checking chapter %s instead", latestChapterNr[0], latest)
+			numbers = []domain.ChapterNumber{latest}
+		}
+	}
+
+	for _, num := range numbers {
+		if ctx.Err() != nil {
+			return
+		}
+
+		downloadChapter(ctx, cfg, mLog, s, selectedManga, num)
+	}
+}
+
+// downloadChapter downloads the available release of the chapter with the given number that is preferred by the
+// source policy
+func downloadChapter(ctx context.Context, cfg *config.AppConfig, mLog zerolog.Logger, s monitoredSource, selectedManga domain.Manga, num domain.ChapterNumber) {
+	releases := selectedManga.AvailableReleases(num, time.Now())
+	if len(releases) == 0 && len(selectedManga.Chapters[num.Key()]) != 0 {
+		err := selectedManga.Chapters[num.Key()][0].CheckAvailable(time.Now())
+		mLog.Debug().Err(err).Msgf("skipping unavailable chapter %s", num)
+		return
+	}
+
+	selectedChapter, ok := s.policy.Select(releases)
+	if !ok {
+		mLog.Error().Msgf("error finding chapter with number %s", num)
+		return
+	}
+
+	t := templater.New(selectedManga, selectedChapter)
+	templatedName := t.ExecTemplate(cfg.Config.NamingTemplate)
+
+	chapterFolder := sanitize.Filename(templatedName)
+	contentPath := filepath.Join(cfg.Config.DownloadLocation, selectedManga.Title, chapterFolder+".cbz")
+
+	if _, err := os.Stat(contentPath); err == nil {
+		mLog.Debug().Msgf("chapter has already been downloaded, skipping %q", templatedName)
+		return
+	}
+
+	if err := s.GetImageURLs(ctx, &selectedChapter); err != nil {
+		if errors.Is(err, domain.ErrChapterUnavailable) {
+			mLog.Debug().Err(err).Msgf("skipping unavailable chapter %s", selectedChapter.Number)
+			return
+		}
+		mLog.Error().Err(err).Msgf("error getting image urls for chapter %s", selectedChapter.Number)
+		return
+	}
+
+	mLog.Info().Msgf("downloading %q", templatedName)
+	result, err := download.Chapter(ctx, contentPath, selectedChapter)
+	if err != nil {
+		mLog.Error().Err(err).Msgf("error downloading chapter %q", templatedName)
+		return
+	}
+	mLog.Info().
+		Int("pages", result.Pages).
+		Str("volume", result.Chapter.Volume).
+		Str("group", result.Chapter.Group).
+		Str("language", result.Chapter.Language).
+		Time("published", result.Chapter.PublishedAt).
+		Msgf("finished downloading %q", templatedName)
+}
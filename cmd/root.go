@@ -23,10 +23,13 @@ For more information and examples, visit https://github.com/nuxencs/mangarr`,
 func init() {
 	initRootFlags()
 	initDownloadFlags()
+	initSearchFlags()
 
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(downloadCmd)
 	rootCmd.AddCommand(monitorCmd)
+	rootCmd.AddCommand(searchCmd)
+	rootCmd.AddCommand(sourcesCmd)
 }
 
 func Execute() {
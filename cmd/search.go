@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"mangarr/internal/source"
+
+	"github.com/spf13/cobra"
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search [query]",
+	Short: "Search a source for manga",
+	Long: `Search a source for manga.
+
+The printed manga value can be used as is for the --manga flag of the download command or the manga field of monitoredManga.
+Custom sources and plugins can be searched if a config is provided.`,
+	Example: `  mangarr search -s mangadex "berserk"`,
+	Args:    cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+
+		loadCustomSources()
+
+		s, err := source.NewSearcher(searchSource)
+		if err != nil {
+			fmt.Println("Invalid source:", err)
+			return
+		}
+
+		query := strings.Join(args, " ")
+
+		results, err := s.Search(ctx, query)
+		if err != nil {
+			fmt.Printf("Failed to search %s for %q: %v\n", searchSource, query, err)
+			return
+		}
+
+		if len(results) == 0 {
+			fmt.Printf("No results found for %q on %s\n", query, searchSource)
+			return
+		}
+
+		for i, result := range results {
+			if i > 0 {
+				fmt.Println()
+			}
+
+			fmt.Println(result.Title)
+			fmt.Printf("  manga:    %s\n", result.ID)
+
+			if len(result.Language) != 0 {
+				fmt.Printf("  language: %s\n", result.Language)
+			}
+			if len(result.URL) != 0 {
+				fmt.Printf("  url:      %s\n", result.URL)
+			}
+			if len(result.CoverURL) != 0 {
+				fmt.Printf("  cover:    %s\n", result.CoverURL)
+			}
+		}
+	},
+}
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"mangarr/internal/buildinfo"
+	"mangarr/internal/config"
+	"mangarr/internal/logger"
+	"mangarr/internal/source"
+
+	"github.com/spf13/cobra"
+)
+
+var sourcesCmd = &cobra.Command{
+	Use:   "sources [name]",
+	Short: "List the available sources or describe a single one",
+	Long: `List the available sources or describe a single one.
+
+The description of a source shows which of manga, group and language it needs and what their values should look like.
+Custom sources and plugins are included if a config is provided.`,
+	Example: `  mangarr sources
+  mangarr sources mangadex`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		loadCustomSources()
+
+		if len(args) == 0 {
+			listSources()
+			return
+		}
+
+		def, ok := source.Lookup(args[0])
+		if !ok {
+			fmt.Printf("Unknown source %q, available sources: %s\n", args[0], strings.Join(source.Keys(), ", "))
+			return
+		}
+
+		describeSource(def)
+	},
+}
+
+// loadCustomSources registers the custom sources and plugins of the config, they are only available if a config
+// is provided
+func loadCustomSources() {
+	if configPath == "" {
+		return
+	}
+
+	cfg := config.Load(configPath, buildinfo.Version)
+
+	if err := source.RegisterScrapers(cfg.Config.Sources); err != nil {
+		fmt.Println("Invalid custom sources:", err)
+	}
+
+	if err := source.RegisterThemes(cfg.Config.Themes); err != nil {
+		fmt.Println("Invalid theme sources:", err)
+	}
+
+	log := logger.New(cfg.Config)
+	if err := source.RegisterPlugins(cfg.Config.Plugins, log.With().Str("module", "plugin").Logger()); err != nil {
+		fmt.Println("Invalid plugins:", err)
+	}
+}
+
+func listSources() {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SOURCE\tNAME\tINPUT\tSEARCH")
+
+	for _, key := range source.Keys() {
+		def, _ := source.Lookup(key)
+
+		var fields []string
+		for _, field := range def.Fields {
+			name := string(field.Name)
+			if !field.Required {
+				name += "?"
+			}
+			fields = append(fields, name)
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", def.Key, def.Name, strings.Join(fields, ", "), yesNo(def.Searchable()))
+	}
+
+	_ = w.Flush()
+
+	fmt.Println()
+	fmt.Println(`Optional input is marked with "?", run "mangarr sources <name>" for details.`)
+}
+
+func describeSource(def source.Definition) {
+	fmt.Printf("%s (%s)\n\n", def.Name, def.Key)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	for _, name := range []source.InputField{source.InputManga, source.InputGroup, source.InputLanguage} {
+		field, ok := def.Field(name)
+		if !ok {
+			fmt.Fprintf(w, "%s\tunused\t\n", name)
+			continue
+		}
+
+		requirement := "optional"
+		if field.Required {
+			requirement = "required"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\n", name, requirement, field.Help)
+		if len(field.Example) != 0 {
+			fmt.Fprintf(w, "\t\te.g. \"%s\"\n", field.Example)
+		}
+	}
+
+	_ = w.Flush()
+	fmt.Println()
+
+	if len(def.Options) != 0 {
+		fmt.Println("options:")
+
+		w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, option := range def.Options {
+			fmt.Fprintf(w, "  %s\t%s\n", option.Name, option.Help)
+			if len(option.Example) != 0 {
+				fmt.Fprintf(w, "  \te.g. \"%s\"\n", option.Example)
+			}
+		}
+		_ = w.Flush()
+		fmt.Println()
+	}
+
+	languages := strings.Join(def.Languages, ", ")
+	switch {
+	case len(languages) != 0:
+	case def.Accepts(source.InputLanguage):
+		languages = "chosen with language"
+	default:
+		languages = "unknown"
+	}
+
+	auth := def.Auth
+	if len(auth) == 0 {
+		auth = "none"
+	}
+
+	rateLimit := def.RateLimit
+	if len(rateLimit) == 0 {
+		rateLimit = "none"
+	}
+
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "search:\t%s\n", yesNo(def.Searchable()))
+	fmt.Fprintf(w, "languages:\t%s\n", languages)
+	fmt.Fprintf(w, "manhwa:\t%s\n", yesNo(def.Manhwa))
+	fmt.Fprintf(w, "auth:\t%s\n", auth)
+	fmt.Fprintf(w, "rate limit:\t%s\n", rateLimit)
+	_ = w.Flush()
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+
+	return "no"
+}
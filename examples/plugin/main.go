@@ -0,0 +1,201 @@
+// Command plugin is a reference implementation of a mangarr source plugin.
+//
+// It serves manga from local JSON catalog files, the manga input is the path to a catalog:
+//
+//	{
+//	  "title": "My Manga",
+//	  "authors": ["Someone"],
+//	  "chapters": [
+//	    {"number": "1", "title": "Start", "group": "Scans", "images": ["https://example.com/1/01.jpg"]}
+//	  ]
+//	}
+//
+// Every invocation reads one request line from stdin and writes one response line to stdout, anything written to
+// stderr ends up in the mangarr log.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+const protocolVersion = 1
+
+type request struct {
+	Version int      `json:"version"`
+	Method  string   `json:"method"`
+	Input   input    `json:"input"`
+	Manga   *manga   `json:"manga,omitempty"`
+	Chapter *chapter `json:"chapter,omitempty"`
+}
+
+type input struct {
+	Manga    string `json:"manga"`
+	Group    string `json:"group,omitempty"`
+	Language string `json:"language,omitempty"`
+}
+
+type response struct {
+	Version  int       `json:"version"`
+	Error    string    `json:"error,omitempty"`
+	Manga    *manga    `json:"manga,omitempty"`
+	Chapters []chapter `json:"chapters,omitempty"`
+	Images   []image   `json:"images,omitempty"`
+}
+
+type manga struct {
+	URL      string   `json:"url"`
+	Title    string   `json:"title"`
+	Metadata metadata `json:"metadata"`
+}
+
+type metadata struct {
+	Authors  []string `json:"authors,omitempty"`
+	Synopsis string   `json:"synopsis,omitempty"`
+	CoverURL string   `json:"coverUrl,omitempty"`
+}
+
+type chapter struct {
+	ID          string    `json:"id,omitempty"`
+	URL         string    `json:"url,omitempty"`
+	Number      string    `json:"number"`
+	Title       string    `json:"title,omitempty"`
+	PublishedAt time.Time `json:"publishedAt"`
+	Group       string    `json:"group,omitempty"`
+	Language    string    `json:"language,omitempty"`
+	Pages       int       `json:"pages,omitempty"`
+}
+
+type image struct {
+	URL string `json:"url"`
+}
+
+type catalog struct {
+	Title       string   `json:"title"`
+	Authors     []string `json:"authors"`
+	Description string   `json:"description"`
+	Cover       string   `json:"cover"`
+	Chapters    []struct {
+		Number   string    `json:"number"`
+		Title    string    `json:"title"`
+		Group    string    `json:"group"`
+		Language string    `json:"language"`
+		Date     time.Time `json:"date"`
+		Images   []string  `json:"images"`
+	} `json:"chapters"`
+}
+
+func main() {
+	log.SetFlags(0)
+
+	resp, err := handle()
+	if err != nil {
+		resp = response{Error: err.Error()}
+	}
+	resp.Version = protocolVersion
+
+	if err := json.NewEncoder(os.Stdout).Encode(resp); err != nil {
+		log.Fatalf("failed to write response: %v", err)
+	}
+}
+
+func handle() (response, error) {
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+
+	if !scanner.Scan() {
+		return response{}, fmt.Errorf("no request received")
+	}
+
+	var req request
+	if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+		return response{}, fmt.Errorf("invalid request: %w", err)
+	}
+
+	if req.Version != protocolVersion {
+		return response{}, fmt.Errorf("unsupported protocol version %d", req.Version)
+	}
+
+	log.Printf("handling %s for %s", req.Method, req.Input.Manga)
+
+	if len(req.Input.Manga) == 0 {
+		return response{}, fmt.Errorf("path to the catalog is required")
+	}
+
+	c, err := readCatalog(req.Input.Manga)
+	if err != nil {
+		return response{}, err
+	}
+
+	switch req.Method {
+	case "validate":
+		return response{}, nil
+	case "getManga":
+		return response{
+			Manga: &manga{
+				URL:   req.Input.Manga,
+				Title: c.Title,
+				Metadata: metadata{
+					Authors:  c.Authors,
+					Synopsis: c.Description,
+					CoverURL: c.Cover,
+				},
+			},
+		}, nil
+	case "getChapters":
+		var chapters []chapter
+		for _, ch := range c.Chapters {
+			if len(req.Input.Group) != 0 && ch.Group != req.Input.Group {
+				continue
+			}
+
+			chapters = append(chapters, chapter{
+				ID:          ch.Number,
+				Number:      ch.Number,
+				Title:       ch.Title,
+				PublishedAt: ch.Date,
+				Group:       ch.Group,
+				Language:    ch.Language,
+				Pages:       len(ch.Images),
+			})
+		}
+		return response{Chapters: chapters}, nil
+	case "getImageURLs":
+		if req.Chapter == nil {
+			return response{}, fmt.Errorf("chapter is required")
+		}
+
+		for _, ch := range c.Chapters {
+			if ch.Number != req.Chapter.ID {
+				continue
+			}
+
+			images := make([]image, 0, len(ch.Images))
+			for _, img := range ch.Images {
+				images = append(images, image{URL: img})
+			}
+			return response{Images: images}, nil
+		}
+		return response{}, fmt.Errorf("chapter %s not found", req.Chapter.Number)
+	default:
+		return response{}, fmt.Errorf("unsupported method %q", req.Method)
+	}
+}
+
+func readCatalog(path string) (catalog, error) {
+	f, err := os.ReadFile(path)
+	if err != nil {
+		return catalog{}, fmt.Errorf("failed to read catalog: %w", err)
+	}
+
+	var c catalog
+	if err := json.Unmarshal(f, &c); err != nil {
+		return catalog{}, fmt.Errorf("failed to parse catalog: %w", err)
+	}
+
+	return c, nil
+}
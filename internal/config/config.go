@@ -31,6 +31,16 @@ downloadLocation: ""
 # This can be used to change how the downloaded chapter will be named
 # The default will result something like this: Manga Ch. 001 - Chapter Title
 #
+# Available variables:
+#   {manga:<.>}      title of the manga
+#   {num:3}          chapter number, padded to the given length
+#   {title: - <.>}   title of the chapter
+#   {vol:2}          volume, padded to the given length, or e.g. {vol: Vol. <.>}
+#   {group: [<.>]}   scanlation group of the chapter
+#   {lang: [<.>]}    language of the chapter
+#   {date:2006-01-02} release date of the chapter in go time layout
+# Variables that have no value for a chapter are left out.
+#
 # Default: {manga:<.>} Ch. {num:3}{title: - <.>}
 #
 namingTemplate: "{manga:<.>} Ch. {num:3}{title: - <.>}"
@@ -43,6 +53,7 @@ checkInterval: 15
 
 # Monitored Manga
 # Here you can define which manga you want to monitor
+# Run "mangarr sources <source>" to see which of manga, group and language a source needs
 #
 monitoredManga:
   # Custom name you can give the entry to easily distinguish between them
@@ -67,14 +78,37 @@ monitoredManga:
     #
     manga: "d8f1d7da-8bb1-407b-8be3-10ac2894d3c6"
 
-    # ID of the scanlation group on MangaDex
+    # IDs of the scanlation groups on MangaDex, separated by commas in order of priority
+    # For every chapter only the releases of the group with the highest priority are used
+    # Add "any" to accept the releases of all other groups and put a "!" in front of an ID to block a group
     #
     group: "310361d7-52dd-4848-9b36-2eb4fcc95e83"
 
-    # Language of the manga on MangaDex
+    # Languages of the chapters on MangaDex, separated by commas in order of preference
+    # For every chapter only the releases in the most preferred language are used
     #
     language: "en"
 
+    # Source specific options, chapters that are only available on other sites are always skipped
+    # quality: "data" for the original images or "data-saver" for compressed ones
+    #
+    #options:
+    #  quality: "data-saver"
+
+    # Names of the groups whose releases you prefer if a chapter has been released more than once, in order of preference
+    #
+    # Optional
+    #
+    #preferredGroups: ["Some Group", "Another Group"]
+
+    # How to choose between releases of a chapter that are equally preferred
+    #
+    # Default: "newest"
+    #
+    # Options: "newest", "pages", "first"
+    #
+    #selection: "newest"
+
   # Custom name you can give the entry to easily distinguish between them
   #
   Kagurabachi:
@@ -82,10 +116,18 @@ monitoredManga:
     #
     source: "mangaplus"
 
-    # ID of the manga on MangaPlus
+    # ID or URL of the manga on MangaPlus
     #
     manga: "100274"
 
+    # Source specific options
+    # language: edition of the manga to download, e.g. "es" or "pt-br", defaults to the edition of the ID
+    # quality: "low", "high" or "super_high", defaults to "super_high"
+    #
+    #options:
+    #  language: "es"
+    #  quality: "high"
+
   # Custom name you can give the entry to easily distinguish between them
   #
   Solo Leveling Ragnarok:
@@ -95,7 +137,7 @@ monitoredManga:
 
     # URL of the manga on Flame Comics
     #
-    manga: "https://flamecomics.xyz/series/solo-leveling-ragnarok/"
+    manga: "https://flamecomics.xyz/series/2"
 
   # Custom name you can give the entry to easily distinguish between them
   #
@@ -115,14 +157,206 @@ monitoredManga:
     #
     source: "cubari"
 
-    # URL of the gist for the manga on Cubari
+    # URL of the gist for the manga or of the manga on the Cubari reader
     #
-    manga: "https://git.io/OPM"
+    manga: "https://cubari.moe/read/gist/OPM/"
 
-    # ID of the scanlation group on MangaDex
+    # Name or key of the group in the groups of the manga
     #
     group: "/r/OnePunchMan"
 
+#  # Custom name you can give the entry to easily distinguish between them
+#  #
+#  Omniscient Reader:
+#    # Source from where the manga should be downloaded
+#    #
+#    source: "comick"
+#
+#    # Slug or URL of the comic on Comick
+#    #
+#    manga: "https://comick.io/comic/omniscient-readers-viewpoint"
+#
+#    # Name of the scanlation group, leave empty to use the chapters of all groups
+#    #
+#    group: "Asura Scans"
+#
+#    # Language of the chapters on Comick
+#    #
+#    language: "en"
+
+#  # Custom name you can give the entry to easily distinguish between them
+#  #
+#  Tower of God:
+#    # Source from where the manga should be downloaded
+#    #
+#    source: "webtoons"
+#
+#    # URL of the series on WEBTOON or its title_no
+#    #
+#    manga: "https://www.webtoons.com/en/fantasy/tower-of-god/list?title_no=95"
+
+#  # Custom name you can give the entry to easily distinguish between them
+#  #
+#  Solo Leveling:
+#    # Source from where the manga should be downloaded
+#    #
+#    source: "feed"
+#
+#    # URL of the RSS or Atom feed
+#    #
+#    manga: "https://mysite.com/feed/"
+#
+#    # Source specific options, run "mangarr sources <source>" to see the options of a source
+#    # Entries linking to a CBZ or ZIP archive are downloaded directly, other entries need imageSelector or scraper
+#    #
+#    options:
+#      titlePattern: "Solo Leveling Chapter (\\d+)"
+#      imageSelector: "#readerarea img"
+
+#  # Custom name you can give the entry to easily distinguish between them
+#  #
+#  Vinland Saga:
+#    # Source from where the manga should be downloaded
+#    #
+#    source: "opds"
+#
+#    # URL of the OPDS 1.2 or 2.0 feed listing the books of the series, e.g. a series feed of Komga or Kavita
+#    # Books are downloaded from their CBZ or ZIP acquisition links or page by page from their page streaming links
+#    #
+#    manga: "http://komga:25600/opds/v1.2/series/0B7VVBRD9SJ5E"
+#
+#    # Credentials for basic auth, if the server needs them
+#    #
+#    options:
+#      username: "user@example.org"
+#      password: "secret"
+
+#  # Custom name you can give the entry to easily distinguish between them
+#  #
+#  Blue Lock:
+#    # Source from where the manga should be downloaded
+#    #
+#    source: "local"
+#
+#    # Inbox directory holding CBZ or ZIP archives and image folders of chapters
+#    # The inbox is watched and new chapters are imported once it has been quiet for a few seconds
+#    #
+#    manga: "/data/inbox"
+#
+#    # Series and chapter are parsed from the paths relative to the inbox, e.g. "Blue Lock Chapter 250.cbz" or "Blue Lock/Chapter 250.cbz"
+#    # The pattern needs the named groups series and chapter, volume and title are optional
+#    #
+#    options:
+#      series: "Blue Lock"
+#      #pattern: "(?P<series>[^/]+)/Vol\\.(?P<volume>\\d+) Ch\\.(?P<chapter>\\d+)"
+
+# Sources Directory
+# Directory with additional custom sources, every .yaml file in it defines a single source named after the file
+# Files that set a theme define a theme source, all others a custom source
+# Relative paths are resolved against the directory of this config file
+#
+# Optional
+#
+#sourcesDirectory: "sources"
+
+# Custom Sources
+# Sites that can be scraped with css selectors can be added here and used like the built-in sources by their name
+# Every field takes a selector relative to the matched element, an attribute to read instead of the text
+# and a pattern whose first capture group is used as value
+#
+# Optional
+#
+#sources:
+#  mysite:
+#    # Name of the site, also used as group of the chapters
+#    name: "My Site"
+#
+#    # Manga URLs need to start with this
+#    urlPrefix: "https://mysite.com/series/"
+#
+#    # Language of the chapters
+#    language: "en"
+#
+#    # Set to true if the site hosts long strip manhwa
+#    manhwa: true
+#
+#    # Element holding the title on the manga page
+#    title:
+#      selector: ".entry-title"
+#
+#    # Elements of the chapters on the manga page, the number defaults to the first number in the text
+#    chapters:
+#      selector: ".eplister li"
+#      url:
+#        selector: "a"
+#        attr: "href"
+#      number:
+#        attr: "data-num"
+#      title:
+#        selector: ".chapternum"
+#        pattern: "Chapter \\S+ - (.+)"
+#
+#    # Images on the chapter page, only urls starting with one of the prefixes are kept if set
+#    images:
+#      selector: "#readerarea img"
+#      attr: "src"
+#      prefixes: ["https://mysite.com"]
+
+# Theme Sources
+# Sites running the MangaStream or Madara WordPress themes only need their base url to be used as a source
+# The selectors of the theme can be overridden if a site deviates from it
+#
+# Optional
+#
+#themes:
+#  othersite:
+#    # Theme of the site
+#    # Options: "mangastream", "madara"
+#    theme: "madara"
+#
+#    # Name of the site, also used as group of the chapters
+#    name: "Other Site"
+#
+#    # Manga URLs need to start with this
+#    baseURL: "https://othersite.com"
+#
+#    # Language of the chapters
+#    language: "en"
+#
+#    # Set to true if the site hosts long strip manhwa
+#    manhwa: false
+#
+#    # Go time layout of the release dates of the chapters
+#    # Default: "January 2, 2006"
+#    dateLayout: "January 2, 2006"
+#
+#    # Only image urls starting with one of these are kept if set
+#    imagePrefixes: []
+#
+#    # Selectors to use instead of the ones of the theme
+#    # Available: title, cover, synopsis, altTitles, genres, status, authors, artists, chapters, chapterUrl,
+#    # chapterNumber, chapterNumberAttr, chapterDate, images, imageAttr
+#    selectors:
+#      images: ".reading-content img.wp-manga-chapter-img"
+
+# Plugins
+# External programs implementing a source, the key is used as source name.
+# See examples/plugin for the protocol and a reference implementation.
+#
+# Optional
+#
+#plugins:
+#  catalog:
+#    # Executable of the plugin, looked up in PATH if it isn't a path
+#    command: "/usr/local/bin/mangarr-catalog"
+#
+#    # Arguments passed to the executable
+#    args: []
+#
+#    # Seconds a single operation may take before the plugin is killed
+#    # Default: 60
+#    timeout: 60
+
 # mangarr logs file
 # If not defined, logs to stdout
 # Make sure to use forward slashes and include the filename with extension. e.g. "logs/mangarr.log", "C:/mangarr/logs/mangarr.log"
@@ -201,6 +435,17 @@ type AppConfig struct {
 }
 
 func New(configPath string, version string) *AppConfig {
+	c := Load(configPath, version)
+
+	if c.Config.DownloadLocation == "" {
+		log.Fatalf("downloadLocation can't be empty, please provide a valid path to the directory you want your downloads to go to")
+	}
+
+	return c
+}
+
+// Load reads the config without requiring a download location, for commands that get it from their flags
+func Load(configPath string, version string) *AppConfig {
 	c := &AppConfig{
 		m: new(sync.Mutex),
 	}
@@ -212,10 +457,7 @@ func New(configPath string, version string) *AppConfig {
 
 	c.load(configPath)
 	c.loadFromEnv()
-
-	if c.Config.DownloadLocation == "" {
-		log.Fatalf("downloadLocation can't be empty, please provide a valid path to the directory you want your downloads to go to")
-	}
+	c.loadSourcesDirectory()
 
 	return c
 }
@@ -229,6 +471,10 @@ func (c *AppConfig) defaults() {
 	viper.SetDefault("logLevel", "DEBUG")
 	viper.SetDefault("logMaxSize", 50)
 	viper.SetDefault("logMaxBackups", 3)
+	viper.SetDefault("sourcesDirectory", "")
+	viper.SetDefault("sources", make(map[string]*domain.ScraperSource))
+	viper.SetDefault("themes", make(map[string]*domain.ThemeSource))
+	viper.SetDefault("plugins", make(map[string]*domain.Plugin))
 }
 
 func (c *AppConfig) loadFromEnv() {
@@ -299,6 +545,75 @@ func (c *AppConfig) load(configPath string) {
 	}
 }
 
+// loadSourcesDirectory adds the source definitions from the yaml files in the sources directory,
+// each file defines a single source named after the file, files that set a theme define a theme source
+func (c *AppConfig) loadSourcesDirectory() {
+	dir := c.Config.SourcesDirectory
+	if dir == "" {
+		return
+	}
+
+	// relative paths are relative to the config file
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(filepath.Dir(viper.ConfigFileUsed()), dir)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		log.Printf("could not read sources directory: %q", err)
+		return
+	}
+
+	if c.Config.Sources == nil {
+		c.Config.Sources = make(map[string]*domain.ScraperSource)
+	}
+
+	if c.Config.Themes == nil {
+		c.Config.Themes = make(map[string]*domain.ThemeSource)
+	}
+
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+
+		key := strings.ToLower(strings.TrimSuffix(entry.Name(), ext))
+		_, isSource := c.Config.Sources[key]
+		if _, isTheme := c.Config.Themes[key]; isSource || isTheme {
+			log.Printf("source %q is defined more than once, ignoring %s", key, entry.Name())
+			continue
+		}
+
+		v := viper.New()
+		v.SetConfigFile(filepath.Join(dir, entry.Name()))
+
+		if err := v.ReadInConfig(); err != nil {
+			log.Printf("could not read source file %s: %q", entry.Name(), err)
+			continue
+		}
+
+		if v.IsSet("theme") {
+			var def domain.ThemeSource
+			if err := v.Unmarshal(&def); err != nil {
+				log.Printf("could not unmarshal source file %s: %q", entry.Name(), err)
+				continue
+			}
+
+			c.Config.Themes[key] = &def
+			continue
+		}
+
+		var def domain.ScraperSource
+		if err := v.Unmarshal(&def); err != nil {
+			log.Printf("could not unmarshal source file %s: %q", entry.Name(), err)
+			continue
+		}
+
+		c.Config.Sources[key] = &def
+	}
+}
+
 func (c *AppConfig) DynamicReload(log logger.Logger) {
 	viper.WatchConfig()
 
@@ -0,0 +1,143 @@
+package domain
+
+import (
+	"cmp"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var chapterLabelPattern = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*(.*)$`)
+
+// ChapterNumber identifies a chapter by the label its source lists it with
+type ChapterNumber struct {
+	// Label is the chapter number exactly as listed by the source, e.g. "1000.15", "10a" or "Extra"
+	Label string
+	// Number is the numeric part of the label, it can't tell parts like "5.1" and "5.10" apart, use Compare to order
+	// chapter numbers
+	Number float64
+	// Suffix is the part of the label following the numeric part, or the whole label if it has no numeric part
+	Suffix string
+
+	numeric bool
+	// integer and fraction hold the digits of the numeric part as written, without the leading zeros of integer
+	integer  string
+	fraction string
+}
+
+// ParseChapterNumber splits a chapter label into its numeric part and suffix
+func ParseChapterNumber(label string) ChapterNumber {
+	trimmed := strings.TrimSpace(label)
+
+	matches := chapterLabelPattern.FindStringSubmatch(trimmed)
+	if matches == nil {
+		return ChapterNumber{Label: label, Suffix: trimmed}
+	}
+
+	number, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return ChapterNumber{Label: label, Suffix: trimmed}
+	}
+
+	integer, fraction, _ := strings.Cut(matches[1], ".")
+
+	return ChapterNumber{
+		Label:    label,
+		Number:   number,
+		Suffix:   matches[2],
+		numeric:  true,
+		integer:  trimZeros(integer),
+		fraction: fraction,
+	}
+}
+
+// IsNumeric reports whether the label starts with a number
+func (n ChapterNumber) IsNumeric() bool {
+	return n.numeric
+}
+
+// String returns the label without the leading zeros of the number, e.g. "0010.50" becomes "10.50"
+func (n ChapterNumber) String() string {
+	return n.Pad(0)
+}
+
+// Pad returns the label with the integer part of the number padded with zeros to width, e.g. "10.5" padded to 3
+// becomes "010.5", labels without a numeric part are returned as is
+func (n ChapterNumber) Pad(width int) string {
+	if !n.numeric {
+		return n.Suffix
+	}
+
+	label := n.integer
+	if padding := width - len(label); padding > 0 {
+		label = strings.Repeat("0", padding) + label
+	}
+	if len(n.fraction) != 0 {
+		label += "." + n.fraction
+	}
+
+	return label + n.Suffix
+}
+
+// Key returns the value chapters are indexed by in Manga.Chapters
+func (n ChapterNumber) Key() string {
+	return strings.ToLower(n.String())
+}
+
+// Compare orders chapter numbers, labels without a numeric part come before all numbered chapters
+func (n ChapterNumber) Compare(other ChapterNumber) int {
+	if n.numeric != other.numeric {
+		if n.numeric {
+			return 1
+		}
+		return -1
+	}
+
+	if c := n.CompareNumber(other); c != 0 {
+		return c
+	}
+
+	return cmp.Compare(strings.ToLower(n.Suffix), strings.ToLower(other.Suffix))
+}
+
+// CompareNumber orders chapter numbers by their numeric part only. The part after the dot is compared as a part
+// number, so 5.9 comes before 5.10
+func (n ChapterNumber) CompareNumber(other ChapterNumber) int {
+	if c := compareDigits(n.integer, other.integer); c != 0 {
+		return c
+	}
+
+	// chapters without a part come before their parts
+	if (len(n.fraction) == 0) != (len(other.fraction) == 0) {
+		if len(n.fraction) == 0 {
+			return -1
+		}
+		return 1
+	}
+
+	if c := compareDigits(trimZeros(n.fraction), trimZeros(other.fraction)); c != 0 {
+		return c
+	}
+
+	// parts only differing in leading zeros like 5.05 and 5.5 still need an order
+	return cmp.Compare(n.fraction, other.fraction)
+}
+
+// compareDigits compares numbers given as digits without leading zeros
+func compareDigits(a, b string) int {
+	if c := cmp.Compare(len(a), len(b)); c != 0 {
+		return c
+	}
+
+	return cmp.Compare(a, b)
+}
+
+// trimZeros trims the leading zeros of digits, keeping a single zero for zero itself
+func trimZeros(digits string) string {
+	trimmed := strings.TrimLeft(digits, "0")
+	if len(trimmed) == 0 && len(digits) != 0 {
+		return "0"
+	}
+
+	return trimmed
+}
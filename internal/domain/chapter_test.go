@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseChapterNumber(t *testing.T) {
+	tests := []struct {
+		label   string
+		number  float64
+		suffix  string
+		numeric bool
+		str     string
+		key     string
+	}{
+		{label: "10", number: 10, numeric: true, str: "10", key: "10"},
+		{label: "10.5", number: 10.5, numeric: true, str: "10.5", key: "10.5"},
+		{label: "0010.50", number: 10.5, numeric: true, str: "10.50", key: "10.50"},
+		{label: "5.10", number: 5.1, numeric: true, str: "5.10", key: "5.10"},
+		{label: "0.5", number: 0.5, numeric: true, str: "0.5", key: "0.5"},
+		{label: " 12 ", number: 12, numeric: true, str: "12", key: "12"},
+		{label: "10a", number: 10, suffix: "a", numeric: true, str: "10a", key: "10a"},
+		{label: "10A", number: 10, suffix: "A", numeric: true, str: "10A", key: "10a"},
+		{label: "1000.15", number: 1000.15, numeric: true, str: "1000.15", key: "1000.15"},
+		{label: "Extra", suffix: "Extra", str: "Extra", key: "extra"},
+		{label: "Oneshot", suffix: "Oneshot", str: "Oneshot", key: "oneshot"},
+		{label: "", str: "", key: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			n := ParseChapterNumber(tt.label)
+
+			if n.Label != tt.label {
+				t.Errorf("Label = %q, want %q", n.Label, tt.label)
+			}
+			if n.Number != tt.number {
+				t.Errorf("Number = %v, want %v", n.Number, tt.number)
+			}
+			if n.Suffix != tt.suffix {
+				t.Errorf("Suffix = %q, want %q", n.Suffix, tt.suffix)
+			}
+			if n.IsNumeric() != tt.numeric {
+				t.Errorf("IsNumeric() = %v, want %v", n.IsNumeric(), tt.numeric)
+			}
+			if n.String() != tt.str {
+				t.Errorf("String() = %q, want %q", n.String(), tt.str)
+			}
+			if n.Key() != tt.key {
+				t.Errorf("Key() = %q, want %q", n.Key(), tt.key)
+			}
+		})
+	}
+}
+
+func TestChapterNumberKeyKeepsDigits(t *testing.T) {
+	if ParseChapterNumber("010.5").Key() != ParseChapterNumber("10.5").Key() {
+		t.Error("010.5 and 10.5 should share a key")
+	}
+	if ParseChapterNumber("5.1").Key() == ParseChapterNumber("5.10").Key() {
+		t.Error("5.1 and 5.10 should not share a key")
+	}
+	if ParseChapterNumber("10").Key() == ParseChapterNumber("10.5").Key() {
+		t.Error("10 and 10.5 should not share a key")
+	}
+	if ParseChapterNumber("Extra").Key() != ParseChapterNumber("extra").Key() {
+		t.Error("Extra and extra should share a key")
+	}
+}
+
+func TestChapterNumberCompare(t *testing.T) {
+	var numbers []ChapterNumber
+	for _, label := range []string{"10.5", "Oneshot", "2", "10", "Extra", "10a", "1", "5.10", "5.9", "5.1"} {
+		numbers = append(numbers, ParseChapterNumber(label))
+	}
+
+	slices.SortFunc(numbers, ChapterNumber.Compare)
+
+	var got []string
+	for _, n := range numbers {
+		got = append(got, n.String())
+	}
+
+	want := []string{"Extra", "Oneshot", "1", "2", "5.1", "5.9", "5.10", "10", "10a", "10.5"}
+	if !slices.Equal(got, want) {
+		t.Errorf("sorted = %v, want %v", got, want)
+	}
+}
+
+func TestMangaChapterNumbers(t *testing.T) {
+	manga := Manga{Chapters: make(map[string][]Chapter)}
+	for _, label := range []string{"10.5", "Extra", "10", "010.5", "10.50"} {
+		manga.AddChapter(Chapter{Number: ParseChapterNumber(label)})
+	}
+
+	if releases := manga.Chapters[ParseChapterNumber("10.5").Key()]; len(releases) != 2 {
+		t.Errorf("releases of 10.5 = %d, want 2", len(releases))
+	}
+
+	var got []string
+	for _, n := range manga.ChapterNumbers() {
+		got = append(got, n.String())
+	}
+
+	want := []string{"Extra", "10", "10.5", "10.50"}
+	if !slices.Equal(got, want) {
+		t.Errorf("ChapterNumbers() = %v, want %v", got, want)
+	}
+}
@@ -11,11 +11,90 @@ type Config struct {
 	LogLevel         string                     `yaml:"LogLevel"`
 	LogMaxSize       int                        `yaml:"logMaxSize"` // in megabytes
 	LogMaxBackups    int                        `yaml:"logMaxBackups"`
+	SourcesDirectory string                     `yaml:"sourcesDirectory"`
+	Sources          map[string]*ScraperSource  `yaml:"sources"`
+	Themes           map[string]*ThemeSource    `yaml:"themes"`
+	Plugins          map[string]*Plugin         `yaml:"plugins"`
 }
 
 type MonitoredManga struct {
-	Source   string `yaml:"source"`
-	Manga    string `yaml:"manga"`
-	Group    string `yaml:"group"`
-	Language string `yaml:"language"`
+	Source          string            `yaml:"source"`
+	Manga           string            `yaml:"manga"`
+	Group           string            `yaml:"group"`
+	Language        string            `yaml:"language"`
+	PreferredGroups []string          `yaml:"preferredGroups"`
+	Selection       string            `yaml:"selection"`
+	Options         map[string]string `yaml:"options"`
+}
+
+// Plugin defines an external executable that implements a source
+type Plugin struct {
+	Command string   `yaml:"command"`
+	Args    []string `yaml:"args"`
+	Timeout int      `yaml:"timeout"` // in seconds
+}
+
+// ScraperSource defines a source that is scraped using css selectors
+type ScraperSource struct {
+	Name      string          `yaml:"name"`
+	URLPrefix string          `yaml:"urlPrefix"`
+	Language  string          `yaml:"language"`
+	Manhwa    bool            `yaml:"manhwa"`
+	Title     ScraperField    `yaml:"title"`
+	Chapters  ScraperChapters `yaml:"chapters"`
+	Images    ScraperImages   `yaml:"images"`
+}
+
+// ScraperField extracts a value from the element matched by Selector, an empty Selector uses the current element
+// and an empty Attr its text, Pattern optionally narrows the value down to its first capture group
+type ScraperField struct {
+	Selector string `yaml:"selector"`
+	Attr     string `yaml:"attr"`
+	Pattern  string `yaml:"pattern"`
+}
+
+// ScraperChapters matches every chapter of a manga page with Selector and extracts its values relative to it
+type ScraperChapters struct {
+	Selector string       `yaml:"selector"`
+	URL      ScraperField `yaml:"url"`
+	Number   ScraperField `yaml:"number"`
+	Title    ScraperField `yaml:"title"`
+}
+
+// ScraperImages matches every image of a chapter page, only urls starting with one of the Prefixes are kept if set
+type ScraperImages struct {
+	Selector string   `yaml:"selector"`
+	Attr     string   `yaml:"attr"`
+	Prefixes []string `yaml:"prefixes"`
+}
+
+// ThemeSource defines a source for a site running one of the supported WordPress manga themes
+type ThemeSource struct {
+	Theme         string         `yaml:"theme"`
+	Name          string         `yaml:"name"`
+	BaseURL       string         `yaml:"baseURL"`
+	Language      string         `yaml:"language"`
+	Manhwa        bool           `yaml:"manhwa"`
+	DateLayout    string         `yaml:"dateLayout"`
+	ImagePrefixes []string       `yaml:"imagePrefixes"`
+	Selectors     ThemeSelectors `yaml:"selectors"`
+}
+
+// ThemeSelectors override the css selectors of a theme, empty selectors keep the default of the theme
+type ThemeSelectors struct {
+	Title             string `yaml:"title"`
+	Cover             string `yaml:"cover"`
+	Synopsis          string `yaml:"synopsis"`
+	AltTitles         string `yaml:"altTitles"`
+	Genres            string `yaml:"genres"`
+	Status            string `yaml:"status"`
+	Authors           string `yaml:"authors"`
+	Artists           string `yaml:"artists"`
+	Chapters          string `yaml:"chapters"`
+	ChapterURL        string `yaml:"chapterUrl"`
+	ChapterNumber     string `yaml:"chapterNumber"`
+	ChapterNumberAttr string `yaml:"chapterNumberAttr"`
+	ChapterDate       string `yaml:"chapterDate"`
+	Images            string `yaml:"images"`
+	ImageAttr         string `yaml:"imageAttr"`
 }
@@ -1,6 +1,17 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrChapterUnavailable is returned for chapters that can't be read at the moment, e.g. because they have expired or
+// are locked behind a paywall
+var ErrChapterUnavailable = errors.New("chapter unavailable")
 
 type Source interface {
 	String() string
@@ -13,16 +24,148 @@ type Source interface {
 type Manga struct {
 	URL      string
 	Title    string
-	Chapters map[float32]Chapter
+	Metadata Metadata
+	// Chapters holds the releases of every chapter indexed by ChapterNumber.Key
+	Chapters map[string][]Chapter
+}
+
+// AddChapter adds a release of a chapter next to the releases already known for its number
+func (m Manga) AddChapter(chapter Chapter) {
+	key := chapter.Number.Key()
+	m.Chapters[key] = append(m.Chapters[key], chapter)
+}
+
+// AvailableReleases returns the releases of the chapter with the given number that can be read at the given time
+func (m Manga) AvailableReleases(num ChapterNumber, at time.Time) []Chapter {
+	var releases []Chapter
+	for _, release := range m.Chapters[num.Key()] {
+		if release.Available(at) {
+			releases = append(releases, release)
+		}
+	}
+
+	return releases
+}
+
+// LatestAvailable returns the number of the highest chapter with a release that can be read at the given time
+func (m Manga) LatestAvailable(at time.Time) (ChapterNumber, bool) {
+	numbers := m.ChapterNumbers()
+	for i := len(numbers) - 1; i >= 0; i-- {
+		if len(m.AvailableReleases(numbers[i], at)) != 0 {
+			return numbers[i], true
+		}
+	}
+
+	return ChapterNumber{}, false
+}
+
+// ChapterNumbers returns the numbers of all chapters in ascending order
+func (m Manga) ChapterNumbers() []ChapterNumber {
+	numbers := make([]ChapterNumber, 0, len(m.Chapters))
+	for _, releases := range m.Chapters {
+		if len(releases) != 0 {
+			numbers = append(numbers, releases[0].Number)
+		}
+	}
+
+	slices.SortFunc(numbers, ChapterNumber.Compare)
+
+	return numbers
+}
+
+// Metadata holds descriptive information about a series as provided by its source
+type Metadata struct {
+	AltTitles        []string
+	Authors          []string
+	Artists          []string
+	Synopsis         string
+	Genres           []string
+	Tags             []string
+	Status           PublicationStatus
+	CoverURL         string
+	ReadingDirection ReadingDirection
+	SourceURL        string
+}
+
+type PublicationStatus string
+
+const (
+	StatusUnknown   PublicationStatus = ""
+	StatusOngoing   PublicationStatus = "ongoing"
+	StatusCompleted PublicationStatus = "completed"
+	StatusHiatus    PublicationStatus = "hiatus"
+	StatusCancelled PublicationStatus = "cancelled"
+)
+
+// ParsePublicationStatus maps the status text used by a source to a PublicationStatus
+func ParsePublicationStatus(status string) PublicationStatus {
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case "ongoing", "publishing", "releasing":
+		return StatusOngoing
+	case "completed", "complete", "finished", "ended":
+		return StatusCompleted
+	case "hiatus", "on hiatus", "on hold":
+		return StatusHiatus
+	case "cancelled", "canceled", "dropped", "discontinued":
+		return StatusCancelled
+	default:
+		return StatusUnknown
+	}
 }
 
+type ReadingDirection string
+
+const (
+	DirectionUnknown     ReadingDirection = ""
+	DirectionRightToLeft ReadingDirection = "rtl"
+	DirectionLeftToRight ReadingDirection = "ltr"
+	DirectionVertical    ReadingDirection = "vertical"
+)
+
 type Chapter struct {
-	ID        string
-	URL       string
-	Number    float32
-	Title     string
-	IsManhwa  bool
-	ImageInfo []ImageInfo
+	ID          string
+	URL         string
+	Number      ChapterNumber
+	Volume      string
+	Title       string
+	PublishedAt time.Time
+	Group       string
+	Language    string
+	Pages       int
+	IsManhwa    bool
+	ImageInfo   []ImageInfo
+	// Headers are sent along with the requests for the images of the chapter, e.g. a Referer the site requires
+	Headers map[string]string
+	// ArchiveURL links to a CBZ or ZIP archive of the chapter, it is downloaded instead of the images if set
+	ArchiveURL string
+	// Local is set by sources importing files from disk, file:// URLs are only read for chapters that have it set
+	Local bool
+	// Reporter is told how fetching the images of the chapter went, if set
+	Reporter ImageReporter
+	// AvailableFrom and AvailableUntil limit when the chapter can be read, they are zero if there is no limit
+	AvailableFrom  time.Time
+	AvailableUntil time.Time
+	// Locked is set for chapters that have to be paid for, they become available at AvailableFrom if it is known
+	Locked bool
+}
+
+// Available reports whether the chapter can be read at the given time
+func (c Chapter) Available(at time.Time) bool {
+	return c.CheckAvailable(at) == nil
+}
+
+// CheckAvailable returns an error wrapping ErrChapterUnavailable if the chapter can't be read at the given time
+func (c Chapter) CheckAvailable(at time.Time) error {
+	switch {
+	case c.Locked && c.AvailableFrom.IsZero():
+		return fmt.Errorf("chapter %s is locked: %w", c.Number, ErrChapterUnavailable)
+	case !c.AvailableFrom.IsZero() && at.Before(c.AvailableFrom):
+		return fmt.Errorf("chapter %s is not released until %s: %w", c.Number, c.AvailableFrom.Format(time.DateTime), ErrChapterUnavailable)
+	case !c.AvailableUntil.IsZero() && !at.Before(c.AvailableUntil):
+		return fmt.Errorf("chapter %s is no longer available since %s: %w", c.Number, c.AvailableUntil.Format(time.DateTime), ErrChapterUnavailable)
+	default:
+		return nil
+	}
 }
 
 type ImageInfo struct {
@@ -30,4 +173,44 @@ type ImageInfo struct {
 	EncryptionKey string
 	Width         float64
 	Height        float64
+	// FallbackURLs are tried in order if the image can't be fetched from ImageURL
+	FallbackURLs []string
+}
+
+// ImageReport describes how fetching an image went
+type ImageReport struct {
+	URL      string
+	Success  bool
+	Bytes    int64
+	Duration time.Duration
+	// Cached is set if the server answered from its cache
+	Cached bool
+}
+
+// ImageReporter is implemented by sources that want to know how fetching their images went, e.g. to report it back
+// to the servers hosting them
+type ImageReporter interface {
+	ReportImage(ctx context.Context, report ImageReport)
+	// Flush waits until the reports made so far are sent or ctx is done
+	Flush(ctx context.Context)
+}
+
+// Watcher is implemented by sources that notice new chapters by themselves instead of having to be checked
+// periodically, all of their chapters are downloaded whenever changed is called
+type Watcher interface {
+	Watch(ctx context.Context, changed func()) error
+}
+
+// Searcher is implemented by sources that can look up manga by name
+type Searcher interface {
+	Search(ctx context.Context, query string) ([]SearchResult, error)
+}
+
+// SearchResult is a candidate returned by a Searcher, ID holds the value to use as manga input for the source
+type SearchResult struct {
+	Title    string
+	ID       string
+	URL      string
+	CoverURL string
+	Language string
 }
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -20,8 +21,18 @@ import (
 	"github.com/avast/retry-go"
 )
 
+// reportFlushTimeout is how long to wait for the image reports of a chapter to be sent after it is downloaded
+const reportFlushTimeout = 10 * time.Second
+
+// Result describes a downloaded chapter
+type Result struct {
+	Path    string
+	Pages   int
+	Chapter domain.Chapter
+}
+
 // Chapter downloads and processes manga chapter images to create a CBZ archive.
-func Chapter(ctx context.Context, contentPath string, chapter domain.Chapter) error {
+func Chapter(ctx context.Context, contentPath string, chapter domain.Chapter) (Result, error) {
 	var wg sync.WaitGroup
 
 	// if chapter.IsManhwa {
@@ -32,10 +43,16 @@ func Chapter(ctx context.Context, contentPath string, chapter domain.Chapter) er
 
 	temp, err := os.MkdirTemp("", "mangarr-*")
 	if err != nil {
-		return err
+		return Result{}, err
 	}
 	defer os.RemoveAll(temp)
 
+	if len(chapter.ArchiveURL) != 0 {
+		if err := archive(ctx, chapter.ArchiveURL, temp, chapter.Headers, chapter.Local); err != nil {
+			return Result{}, err
+		}
+	}
+
 	for i, imageInfo := range chapter.ImageInfo {
 		wg.Add(1)
 
@@ -46,21 +63,38 @@ func Chapter(ctx context.Context, contentPath string, chapter domain.Chapter) er
 
 			filenameNoExt := filepath.Join(temp, fmt.Sprintf("%03d", i+1))
 
-			if len(imageInfo.EncryptionKey) != 0 {
-				if err = decryptImage(ctx, imageInfo.ImageURL, imageInfo.EncryptionKey, filenameNoExt); err != nil {
-					fmt.Printf("error decrypting and downloading file: %q", err)
-					return
+			// the fallbacks are only tried once all attempts of the previous url have failed
+			var err error
+			for _, imageURL := range append([]string{imageInfo.ImageURL}, imageInfo.FallbackURLs...) {
+				if len(imageInfo.EncryptionKey) != 0 {
+					err = decryptImage(ctx, imageURL, imageInfo.EncryptionKey, filenameNoExt, chapter.Headers)
+				} else {
+					err = singleFile(ctx, imageURL, filenameNoExt, chapter.Headers, chapter.Reporter, chapter.Local)
+				}
+
+				if err == nil || ctx.Err() != nil {
+					break
 				}
-			} else {
-				if err = singleFile(ctx, imageInfo.ImageURL, filenameNoExt); err != nil {
+			}
+
+			if err != nil {
+				if len(imageInfo.EncryptionKey) != 0 {
+					fmt.Printf("error decrypting and downloading file: %q", err)
+				} else {
 					fmt.Printf("error downloading file: %q", err)
-					return
 				}
 			}
 		}()
 	}
 	wg.Wait()
 
+	// reports are sent in the background, give them a moment to go out before moving on
+	if chapter.Reporter != nil {
+		flushCtx, cancel := context.WithTimeout(ctx, reportFlushTimeout)
+		chapter.Reporter.Flush(flushCtx)
+		cancel()
+	}
+
 	// if chapter.IsManhwa {
 	// 	 err = files.CreatePDF(temp, outputPath)
 	// 	 if err != nil {
@@ -73,38 +107,75 @@ func Chapter(ctx context.Context, contentPath string, chapter domain.Chapter) er
 	// 	 }
 	// }
 
+	pages, err := os.ReadDir(temp)
+	if err != nil {
+		return Result{}, err
+	}
+
 	if err := files.CreateCbzArchive(temp, contentPath, chapter.IsManhwa); err != nil {
-		return err
+		return Result{}, err
 	}
 
-	return nil
+	return Result{
+		Path:    contentPath,
+		Pages:   len(pages),
+		Chapter: chapter,
+	}, nil
 }
 
-// singleFile downloads a single file
-func singleFile(ctx context.Context, url, filenameNoExt string) error {
+// singleFile downloads a single file, headers are added to the request and every attempt is reported to the reporter
+// if it is set, file urls are only copied if local is set
+func singleFile(ctx context.Context, url, filenameNoExt string, headers map[string]string, reporter domain.ImageReporter, local bool) error {
+	path, ok, err := localPath(url, local)
+	if err != nil {
+		return err
+	}
+	if ok {
+		return copyFile(path, filenameNoExt+strings.ToLower(filepath.Ext(path)))
+	}
+
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("User-Agent", "mangarr")
+	setHeaders(req, headers)
 
 	client := http.Client{
 		Timeout:   60 * time.Second,
 		Transport: sharedhttp.Transport,
 	}
 
-	retryErr := retry.Do(func() error {
+	retryErr := retry.Do(func() (err error) {
+		start := time.Now()
+		var written int64
+		var cached bool
+
+		if reporter != nil {
+			defer func() {
+				reporter.ReportImage(ctx, domain.ImageReport{
+					URL:      url,
+					Success:  err == nil,
+					Bytes:    written,
+					Duration: time.Since(start),
+					Cached:   cached,
+				})
+			}()
+		}
+
 		resp, err := client.Do(req)
 		if err != nil {
 			return fmt.Errorf("failed to get image: %w", err)
 		}
 		defer resp.Body.Close()
 
-		if err := sharedhttp.CheckStatusCode(resp.StatusCode); err != nil {
+		if err := sharedhttp.CheckResponse(resp); err != nil {
 			return err
 		}
 
+		cached = strings.HasPrefix(resp.Header.Get("X-Cache"), "HIT")
+
 		filename, err := appendImageExtension(resp, filenameNoExt)
 		if err != nil {
 			return err
@@ -120,7 +191,7 @@ func singleFile(ctx context.Context, url, filenameNoExt string) error {
 		writeBuf := bufio.NewWriter(out)
 		defer writeBuf.Flush()
 
-		_, err = io.Copy(writeBuf, readBuf)
+		written, err = io.Copy(writeBuf, readBuf)
 		if err != nil {
 			return err
 		}
@@ -130,19 +201,21 @@ func singleFile(ctx context.Context, url, filenameNoExt string) error {
 		retry.Delay(time.Second*3),
 		retry.Attempts(3),
 		retry.MaxJitter(time.Second*1),
+		retry.DelayType(sharedhttp.RetryAfterDelay),
 	)
 
 	return retryErr
 }
 
 // decryptImage fetches an image from the URL and decrypts it with the given encryption key.
-func decryptImage(ctx context.Context, url string, encryptionHex string, filenameNoExt string) error {
+func decryptImage(ctx context.Context, url string, encryptionHex string, filenameNoExt string, headers map[string]string) error {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("User-Agent", "mangarr")
+	setHeaders(req, headers)
 
 	client := http.Client{
 		Timeout:   60 * time.Second,
@@ -156,7 +229,7 @@ func decryptImage(ctx context.Context, url string, encryptionHex string, filenam
 		}
 		defer resp.Body.Close()
 
-		if err := sharedhttp.CheckStatusCode(resp.StatusCode); err != nil {
+		if err := sharedhttp.CheckResponse(resp); err != nil {
 			return err
 		}
 
@@ -201,11 +274,133 @@ func decryptImage(ctx context.Context, url string, encryptionHex string, filenam
 		retry.Delay(time.Second*3),
 		retry.Attempts(3),
 		retry.MaxJitter(time.Second*1),
+		retry.DelayType(sharedhttp.RetryAfterDelay),
 	)
 
 	return retryErr
 }
 
+// archive downloads the archive of a chapter and extracts its images to destDir, file urls are only read if local is
+// set
+func archive(ctx context.Context, url, destDir string, headers map[string]string, local bool) error {
+	path, ok, err := localPath(url, local)
+	if err != nil {
+		return err
+	}
+	if ok {
+		return extractArchive(path, destDir)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "mangarr")
+	setHeaders(req, headers)
+
+	client := http.Client{
+		Timeout:   10 * time.Minute,
+		Transport: sharedhttp.Transport,
+	}
+
+	out, err := os.CreateTemp("", "mangarr-*.zip")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(out.Name())
+	defer out.Close()
+
+	retryErr := retry.Do(func() error {
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to get archive: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if err := sharedhttp.CheckStatusCode(resp.StatusCode); err != nil {
+			return err
+		}
+
+		// start over in case an earlier attempt failed halfway
+		if err := out.Truncate(0); err != nil {
+			return retry.Unrecoverable(err)
+		}
+		if _, err := out.Seek(0, io.SeekStart); err != nil {
+			return retry.Unrecoverable(err)
+		}
+
+		_, err = io.Copy(out, resp.Body)
+		return err
+	},
+		retry.Delay(time.Second*3),
+		retry.Attempts(3),
+		retry.MaxJitter(time.Second*1),
+	)
+	if retryErr != nil {
+		return retryErr
+	}
+
+	return extractArchive(out.Name(), destDir)
+}
+
+// extractArchive extracts the images of the archive at path to destDir
+func extractArchive(path, destDir string) error {
+	pages, err := files.ExtractImages(path, destDir)
+	if err != nil {
+		return fmt.Errorf("failed to extract archive: %w", err)
+	}
+
+	if pages == 0 {
+		return fmt.Errorf("archive contains no images: %s", path)
+	}
+
+	return nil
+}
+
+// localPath returns the path of a file url, sources that import local files link to them this way. File urls of
+// chapters that aren't local are rejected, otherwise any remote source could have local files copied into the library
+func localPath(url string, local bool) (string, bool, error) {
+	path, ok := strings.CutPrefix(url, "file://")
+	if !ok {
+		return "", false, nil
+	}
+
+	if !local {
+		return "", false, fmt.Errorf("refusing to read local file of a chapter from a remote source: %s", url)
+	}
+
+	return filepath.FromSlash(path), true, nil
+}
+
+// copyFile copies the file at src to dst
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	writeBuf := bufio.NewWriter(out)
+	defer writeBuf.Flush()
+
+	_, err = io.Copy(writeBuf, in)
+	return err
+}
+
+// setHeaders sets the headers on the request, replacing any already set
+func setHeaders(req *http.Request, headers map[string]string) {
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+}
+
 func appendImageExtension(resp *http.Response, filename string) (string, error) {
 	contentType := resp.Header.Get("Content-Type")
 
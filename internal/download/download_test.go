@@ -0,0 +1,38 @@
+package download
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocalFilesOnlyReadForLocalChapters(t *testing.T) {
+	dir := t.TempDir()
+
+	src := filepath.Join(dir, "secret.jpg")
+	if err := os.WriteFile(src, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	url := "file://" + filepath.ToSlash(src)
+
+	remote := filepath.Join(dir, "remote")
+	if err := singleFile(context.Background(), url, remote, nil, nil, false); err == nil {
+		t.Error("singleFile read a file url of a remote chapter")
+	}
+	if _, err := os.Stat(remote + ".jpg"); !os.IsNotExist(err) {
+		t.Errorf("file of remote chapter was copied: %v", err)
+	}
+
+	if err := archive(context.Background(), url, dir, nil, false); err == nil {
+		t.Error("archive read a file url of a remote chapter")
+	}
+
+	local := filepath.Join(dir, "local")
+	if err := singleFile(context.Background(), url, local, nil, nil, true); err != nil {
+		t.Fatalf("singleFile: %v", err)
+	}
+	if _, err := os.Stat(local + ".jpg"); err != nil {
+		t.Errorf("file of local chapter wasn't copied: %v", err)
+	}
+}
@@ -3,10 +3,15 @@ package files
 import (
 	"archive/zip"
 	"bufio"
+	"fmt"
 	"image"
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+
+	"mangarr/internal/utils"
 
 	"github.com/go-pdf/fpdf"
 	_ "golang.org/x/image/webp" // needed to decode webp
@@ -161,3 +166,59 @@ func addFileToZip(zipWriter *zip.Writer, filePath, fileName string) error {
 	_, err = io.Copy(writer, readerBuf)
 	return err
 }
+
+// ExtractImages extracts the images of the zip archive at archivePath to destDir in the natural order of their names,
+// they are numbered instead of keeping their names and any other files are skipped
+func ExtractImages(archivePath, destDir string) (int, error) {
+	reader, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+
+	var images []*zip.File
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+
+		switch strings.ToLower(filepath.Ext(file.Name)) {
+		case ".jpg", ".jpeg", ".png", ".gif", ".webp":
+			images = append(images, file)
+		}
+	}
+
+	slices.SortFunc(images, func(a, b *zip.File) int {
+		return utils.NaturalCompare(a.Name, b.Name)
+	})
+
+	for i, file := range images {
+		name := fmt.Sprintf("%03d%s", i+1, strings.ToLower(filepath.Ext(file.Name)))
+		if err := extractFile(file, filepath.Join(destDir, name)); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(images), nil
+}
+
+// extractFile writes a single file of a zip archive to path
+func extractFile(file *zip.File, path string) error {
+	src, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	writeBuf := bufio.NewWriter(out)
+	defer writeBuf.Flush()
+
+	_, err = io.Copy(writeBuf, src)
+	return err
+}
@@ -0,0 +1,58 @@
+package files
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExtractImagesInNaturalOrder(t *testing.T) {
+	dir := t.TempDir()
+	archivePath := filepath.Join(dir, "chapter.cbz")
+
+	f, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	zipWriter := zip.NewWriter(f)
+	for _, name := range []string{"10.jpg", "2.jpg", "1.jpg", "notes.txt"} {
+		w, err := zipWriter.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	destDir := filepath.Join(dir, "pages")
+	if err := os.Mkdir(destDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	pages, err := ExtractImages(archivePath, destDir)
+	if err != nil {
+		t.Fatalf("ExtractImages: %v", err)
+	}
+	if pages != 3 {
+		t.Errorf("pages = %d, want 3", pages)
+	}
+
+	for page, want := range map[string]string{"001.jpg": "1.jpg", "002.jpg": "2.jpg", "003.jpg": "10.jpg"} {
+		got, err := os.ReadFile(filepath.Join(destDir, page))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("page %s = %s, want %s", page, got, want)
+		}
+	}
+}
@@ -1,19 +1,16 @@
 package parse
 
 import (
-	"cmp"
 	"fmt"
-	"slices"
-	"strconv"
 	"strings"
 
 	"mangarr/internal/domain"
 )
 
 // ChapterSelection parses the user input for ranges and parts
-func ChapterSelection(input string, availableChapters map[float32]domain.Chapter) ([]float32, error) {
+func ChapterSelection(input string, manga domain.Manga) ([]domain.ChapterNumber, error) {
 	parts := strings.Split(input, ",")
-	uniqueChapters := make(map[float32]bool)
+	uniqueChapters := make(map[string]domain.ChapterNumber)
 
 	for _, part := range parts {
 		if strings.Contains(part, "-") {
@@ -26,57 +23,55 @@ func ChapterSelection(input string, availableChapters map[float32]domain.Chapter
 				return nil, err
 			}
 
-			for chapter := range availableChapters {
-				if chapter >= start && chapter <= end {
-					uniqueChapters[chapter] = true
+			for _, chapter := range manga.ChapterNumbers() {
+				// ranges only compare the numeric part, so 1-10 includes 10a but none of the unnumbered chapters
+				if chapter.IsNumeric() && chapter.CompareNumber(start) >= 0 && chapter.CompareNumber(end) <= 0 {
+					uniqueChapters[chapter.Key()] = chapter
 				}
 			}
 		} else {
-			chapter, err := strconv.ParseFloat(strings.TrimSpace(part), 32)
-			if err != nil {
-				return nil, err
+			if len(strings.TrimSpace(part)) == 0 {
+				return nil, fmt.Errorf("invalid chapter: %q", part)
 			}
-			uniqueChapters[float32(chapter)] = true
+
+			chapter := domain.ParseChapterNumber(part)
+			uniqueChapters[chapter.Key()] = chapter
 		}
 	}
 
-	selectedChapters := make([]float32, 0, len(uniqueChapters))
-	for chapterNumber := range uniqueChapters {
+	selectedChapters := make([]domain.ChapterNumber, 0, len(uniqueChapters))
+	for _, chapterNumber := range uniqueChapters {
 		selectedChapters = append(selectedChapters, chapterNumber)
 	}
 
 	return selectedChapters, nil
 }
 
-// getRange parses the user input for chapter ranges
-func getRange(rangeParts []string) (float32, float32, error) {
-	start, err := strconv.ParseFloat(strings.TrimSpace(rangeParts[0]), 32)
-	if err != nil {
-		return 0, 0, fmt.Errorf("invalid start of range: %s", rangeParts[0])
+// getRange parses the user input for chapter ranges, both ends need to be numeric
+func getRange(rangeParts []string) (domain.ChapterNumber, domain.ChapterNumber, error) {
+	start := domain.ParseChapterNumber(rangeParts[0])
+	if !start.IsNumeric() {
+		return domain.ChapterNumber{}, domain.ChapterNumber{}, fmt.Errorf("invalid start of range: %s", rangeParts[0])
 	}
-	end, err := strconv.ParseFloat(strings.TrimSpace(rangeParts[1]), 32)
-	if err != nil {
-		return 0, 0, fmt.Errorf("invalid end of range: %s", rangeParts[1])
+	end := domain.ParseChapterNumber(rangeParts[1])
+	if !end.IsNumeric() {
+		return domain.ChapterNumber{}, domain.ChapterNumber{}, fmt.Errorf("invalid end of range: %s", rangeParts[1])
 	}
 
-	if start > end {
-		return 0, 0, fmt.Errorf("start of range should not be greater than end: %s-%s", rangeParts[0], rangeParts[1])
+	if start.CompareNumber(end) > 0 {
+		return domain.ChapterNumber{}, domain.ChapterNumber{}, fmt.Errorf("start of range should not be greater than end: %s-%s", rangeParts[0], rangeParts[1])
 	}
 
-	return float32(start), float32(end), nil
+	return start, end, nil
 }
 
-// GetMinAndMaxKeys returns the lowest and highest keys from a map that has keys that can be ordered
-func GetMinAndMaxKeys[K cmp.Ordered, V any](someMap map[K]V) ([]K, []K, error) {
-	if len(someMap) == 0 {
-		var zero []K
-		return zero, zero, fmt.Errorf("map is empty")
-	}
-
-	keys := make([]K, 0, len(someMap))
-	for key := range someMap {
-		keys = append(keys, key)
+// GetFirstAndLatestChapters returns the lowest and highest chapter numbers of a manga
+func GetFirstAndLatestChapters(manga domain.Manga) ([]domain.ChapterNumber, []domain.ChapterNumber, error) {
+	numbers := manga.ChapterNumbers()
+	if len(numbers) == 0 {
+		var zero []domain.ChapterNumber
+		return zero, zero, fmt.Errorf("manga has no chapters")
 	}
 
-	return []K{slices.Min(keys)}, []K{slices.Max(keys)}, nil
+	return []domain.ChapterNumber{numbers[0]}, []domain.ChapterNumber{numbers[len(numbers)-1]}, nil
 }
@@ -1282,6 +1282,104 @@ func (x *TitleDetailView) GetChapterListGroup() []*ChapterGroup {
 	return nil
 }
 
+type AllTitlesGroup struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TheTitle string   `protobuf:"bytes,1,opt,name=the_title,json=theTitle,proto3" json:"the_title,omitempty"`
+	Titles   []*Title `protobuf:"bytes,2,rep,name=titles,proto3" json:"titles,omitempty"`
+}
+
+func (x *AllTitlesGroup) Reset() {
+	*x = AllTitlesGroup{}
+	mi := &file_internal_protobuf_response_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AllTitlesGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AllTitlesGroup) ProtoMessage() {}
+
+func (x *AllTitlesGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_protobuf_response_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AllTitlesGroup.ProtoReflect.Descriptor instead.
+func (*AllTitlesGroup) Descriptor() ([]byte, []int) {
+	return file_internal_protobuf_response_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *AllTitlesGroup) GetTheTitle() string {
+	if x != nil {
+		return x.TheTitle
+	}
+	return ""
+}
+
+func (x *AllTitlesGroup) GetTitles() []*Title {
+	if x != nil {
+		return x.Titles
+	}
+	return nil
+}
+
+type AllTitlesViewV2 struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AllTitlesGroup []*AllTitlesGroup `protobuf:"bytes,1,rep,name=all_titles_group,json=allTitlesGroup,proto3" json:"all_titles_group,omitempty"`
+}
+
+func (x *AllTitlesViewV2) Reset() {
+	*x = AllTitlesViewV2{}
+	mi := &file_internal_protobuf_response_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AllTitlesViewV2) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AllTitlesViewV2) ProtoMessage() {}
+
+func (x *AllTitlesViewV2) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_protobuf_response_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AllTitlesViewV2.ProtoReflect.Descriptor instead.
+func (*AllTitlesViewV2) Descriptor() ([]byte, []int) {
+	return file_internal_protobuf_response_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *AllTitlesViewV2) GetAllTitlesGroup() []*AllTitlesGroup {
+	if x != nil {
+		return x.AllTitlesGroup
+	}
+	return nil
+}
+
 type SuccessResult struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1289,11 +1387,12 @@ type SuccessResult struct {
 
 	TitleDetailView *TitleDetailView `protobuf:"bytes,8,opt,name=title_detail_view,json=titleDetailView,proto3" json:"title_detail_view,omitempty"`
 	MangaViewer     *MangaViewer     `protobuf:"bytes,10,opt,name=manga_viewer,json=mangaViewer,proto3" json:"manga_viewer,omitempty"`
+	AllTitlesViewV2 *AllTitlesViewV2 `protobuf:"bytes,25,opt,name=all_titles_view_v2,json=allTitlesViewV2,proto3" json:"all_titles_view_v2,omitempty"`
 }
 
 func (x *SuccessResult) Reset() {
 	*x = SuccessResult{}
-	mi := &file_internal_protobuf_response_proto_msgTypes[15]
+	mi := &file_internal_protobuf_response_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1305,7 +1404,7 @@ func (x *SuccessResult) String() string {
 func (*SuccessResult) ProtoMessage() {}
 
 func (x *SuccessResult) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_protobuf_response_proto_msgTypes[15]
+	mi := &file_internal_protobuf_response_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1318,7 +1417,7 @@ func (x *SuccessResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SuccessResult.ProtoReflect.Descriptor instead.
 func (*SuccessResult) Descriptor() ([]byte, []int) {
-	return file_internal_protobuf_response_proto_rawDescGZIP(), []int{15}
+	return file_internal_protobuf_response_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *SuccessResult) GetTitleDetailView() *TitleDetailView {
@@ -1335,6 +1434,13 @@ func (x *SuccessResult) GetMangaViewer() *MangaViewer {
 	return nil
 }
 
+func (x *SuccessResult) GetAllTitlesViewV2() *AllTitlesViewV2 {
+	if x != nil {
+		return x.AllTitlesViewV2
+	}
+	return nil
+}
+
 type Response struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -1345,7 +1451,7 @@ type Response struct {
 
 func (x *Response) Reset() {
 	*x = Response{}
-	mi := &file_internal_protobuf_response_proto_msgTypes[16]
+	mi := &file_internal_protobuf_response_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1357,7 +1463,7 @@ func (x *Response) String() string {
 func (*Response) ProtoMessage() {}
 
 func (x *Response) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_protobuf_response_proto_msgTypes[16]
+	mi := &file_internal_protobuf_response_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1370,7 +1476,7 @@ func (x *Response) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Response.ProtoReflect.Descriptor instead.
 func (*Response) Descriptor() ([]byte, []int) {
-	return file_internal_protobuf_response_proto_rawDescGZIP(), []int{16}
+	return file_internal_protobuf_response_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *Response) GetSuccess() *SuccessResult {
@@ -1394,7 +1500,7 @@ type AdNetworkList_AdNetwork struct {
 
 func (x *AdNetworkList_AdNetwork) Reset() {
 	*x = AdNetworkList_AdNetwork{}
-	mi := &file_internal_protobuf_response_proto_msgTypes[17]
+	mi := &file_internal_protobuf_response_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1406,7 +1512,7 @@ func (x *AdNetworkList_AdNetwork) String() string {
 func (*AdNetworkList_AdNetwork) ProtoMessage() {}
 
 func (x *AdNetworkList_AdNetwork) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_protobuf_response_proto_msgTypes[17]
+	mi := &file_internal_protobuf_response_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1467,7 +1573,7 @@ type AdNetworkList_AdNetwork_Facebook struct {
 
 func (x *AdNetworkList_AdNetwork_Facebook) Reset() {
 	*x = AdNetworkList_AdNetwork_Facebook{}
-	mi := &file_internal_protobuf_response_proto_msgTypes[18]
+	mi := &file_internal_protobuf_response_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1479,7 +1585,7 @@ func (x *AdNetworkList_AdNetwork_Facebook) String() string {
 func (*AdNetworkList_AdNetwork_Facebook) ProtoMessage() {}
 
 func (x *AdNetworkList_AdNetwork_Facebook) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_protobuf_response_proto_msgTypes[18]
+	mi := &file_internal_protobuf_response_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1512,7 +1618,7 @@ type AdNetworkList_AdNetwork_Admob struct {
 
 func (x *AdNetworkList_AdNetwork_Admob) Reset() {
 	*x = AdNetworkList_AdNetwork_Admob{}
-	mi := &file_internal_protobuf_response_proto_msgTypes[19]
+	mi := &file_internal_protobuf_response_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1524,7 +1630,7 @@ func (x *AdNetworkList_AdNetwork_Admob) String() string {
 func (*AdNetworkList_AdNetwork_Admob) ProtoMessage() {}
 
 func (x *AdNetworkList_AdNetwork_Admob) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_protobuf_response_proto_msgTypes[19]
+	mi := &file_internal_protobuf_response_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1557,7 +1663,7 @@ type AdNetworkList_AdNetwork_Mopub struct {
 
 func (x *AdNetworkList_AdNetwork_Mopub) Reset() {
 	*x = AdNetworkList_AdNetwork_Mopub{}
-	mi := &file_internal_protobuf_response_proto_msgTypes[20]
+	mi := &file_internal_protobuf_response_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1569,7 +1675,7 @@ func (x *AdNetworkList_AdNetwork_Mopub) String() string {
 func (*AdNetworkList_AdNetwork_Mopub) ProtoMessage() {}
 
 func (x *AdNetworkList_AdNetwork_Mopub) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_protobuf_response_proto_msgTypes[20]
+	mi := &file_internal_protobuf_response_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1602,7 +1708,7 @@ type AdNetworkList_AdNetwork_Adsense struct {
 
 func (x *AdNetworkList_AdNetwork_Adsense) Reset() {
 	*x = AdNetworkList_AdNetwork_Adsense{}
-	mi := &file_internal_protobuf_response_proto_msgTypes[21]
+	mi := &file_internal_protobuf_response_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1614,7 +1720,7 @@ func (x *AdNetworkList_AdNetwork_Adsense) String() string {
 func (*AdNetworkList_AdNetwork_Adsense) ProtoMessage() {}
 
 func (x *AdNetworkList_AdNetwork_Adsense) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_protobuf_response_proto_msgTypes[21]
+	mi := &file_internal_protobuf_response_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1647,7 +1753,7 @@ type AdNetworkList_AdNetwork_Applovin struct {
 
 func (x *AdNetworkList_AdNetwork_Applovin) Reset() {
 	*x = AdNetworkList_AdNetwork_Applovin{}
-	mi := &file_internal_protobuf_response_proto_msgTypes[22]
+	mi := &file_internal_protobuf_response_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1659,7 +1765,7 @@ func (x *AdNetworkList_AdNetwork_Applovin) String() string {
 func (*AdNetworkList_AdNetwork_Applovin) ProtoMessage() {}
 
 func (x *AdNetworkList_AdNetwork_Applovin) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_protobuf_response_proto_msgTypes[22]
+	mi := &file_internal_protobuf_response_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1693,7 +1799,7 @@ type Popup_Button struct {
 
 func (x *Popup_Button) Reset() {
 	*x = Popup_Button{}
-	mi := &file_internal_protobuf_response_proto_msgTypes[23]
+	mi := &file_internal_protobuf_response_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1705,7 +1811,7 @@ func (x *Popup_Button) String() string {
 func (*Popup_Button) ProtoMessage() {}
 
 func (x *Popup_Button) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_protobuf_response_proto_msgTypes[23]
+	mi := &file_internal_protobuf_response_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1749,7 +1855,7 @@ type Popup_OSDefault struct {
 
 func (x *Popup_OSDefault) Reset() {
 	*x = Popup_OSDefault{}
-	mi := &file_internal_protobuf_response_proto_msgTypes[24]
+	mi := &file_internal_protobuf_response_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1761,7 +1867,7 @@ func (x *Popup_OSDefault) String() string {
 func (*Popup_OSDefault) ProtoMessage() {}
 
 func (x *Popup_OSDefault) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_protobuf_response_proto_msgTypes[24]
+	mi := &file_internal_protobuf_response_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1825,7 +1931,7 @@ type Popup_AppDefault struct {
 
 func (x *Popup_AppDefault) Reset() {
 	*x = Popup_AppDefault{}
-	mi := &file_internal_protobuf_response_proto_msgTypes[25]
+	mi := &file_internal_protobuf_response_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1837,7 +1943,7 @@ func (x *Popup_AppDefault) String() string {
 func (*Popup_AppDefault) ProtoMessage() {}
 
 func (x *Popup_AppDefault) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_protobuf_response_proto_msgTypes[25]
+	mi := &file_internal_protobuf_response_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1892,7 +1998,7 @@ type Popup_MovieReward struct {
 
 func (x *Popup_MovieReward) Reset() {
 	*x = Popup_MovieReward{}
-	mi := &file_internal_protobuf_response_proto_msgTypes[26]
+	mi := &file_internal_protobuf_response_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1904,7 +2010,7 @@ func (x *Popup_MovieReward) String() string {
 func (*Popup_MovieReward) ProtoMessage() {}
 
 func (x *Popup_MovieReward) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_protobuf_response_proto_msgTypes[26]
+	mi := &file_internal_protobuf_response_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2241,22 +2347,37 @@ var file_internal_protobuf_response_proto_rawDesc = []byte{
 	0x6c, 0x69, 0x73, 0x74, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x1c, 0x20, 0x03, 0x28, 0x0b,
 	0x32, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x43, 0x68, 0x61, 0x70,
 	0x74, 0x65, 0x72, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x10, 0x63, 0x68, 0x61, 0x70, 0x74, 0x65,
-	0x72, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x22, 0x90, 0x01, 0x0a, 0x0d, 0x53,
-	0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x45, 0x0a, 0x11,
-	0x74, 0x69, 0x74, 0x6c, 0x65, 0x5f, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x5f, 0x76, 0x69, 0x65,
-	0x77, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
-	0x75, 0x66, 0x2e, 0x54, 0x69, 0x74, 0x6c, 0x65, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x56, 0x69,
-	0x65, 0x77, 0x52, 0x0f, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x56,
-	0x69, 0x65, 0x77, 0x12, 0x38, 0x0a, 0x0c, 0x6d, 0x61, 0x6e, 0x67, 0x61, 0x5f, 0x76, 0x69, 0x65,
-	0x77, 0x65, 0x72, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d, 0x61, 0x6e, 0x67, 0x61, 0x56, 0x69, 0x65, 0x77, 0x65, 0x72,
-	0x52, 0x0b, 0x6d, 0x61, 0x6e, 0x67, 0x61, 0x56, 0x69, 0x65, 0x77, 0x65, 0x72, 0x22, 0x3d, 0x0a,
-	0x08, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x07, 0x73, 0x75, 0x63,
-	0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x52, 0x65, 0x73,
-	0x75, 0x6c, 0x74, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x42, 0x13, 0x5a, 0x11,
-	0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
-	0x66, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x72, 0x4c, 0x69, 0x73, 0x74, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x22, 0x56, 0x0a, 0x0e, 0x41, 0x6c,
+	0x6c, 0x54, 0x69, 0x74, 0x6c, 0x65, 0x73, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x1b, 0x0a, 0x09,
+	0x74, 0x68, 0x65, 0x5f, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x74, 0x68, 0x65, 0x54, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x27, 0x0a, 0x06, 0x74, 0x69, 0x74,
+	0x6c, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x74, 0x6c, 0x65, 0x52, 0x06, 0x74, 0x69, 0x74, 0x6c,
+	0x65, 0x73, 0x22, 0x55, 0x0a, 0x0f, 0x41, 0x6c, 0x6c, 0x54, 0x69, 0x74, 0x6c, 0x65, 0x73, 0x56,
+	0x69, 0x65, 0x77, 0x56, 0x32, 0x12, 0x42, 0x0a, 0x10, 0x61, 0x6c, 0x6c, 0x5f, 0x74, 0x69, 0x74,
+	0x6c, 0x65, 0x73, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x41, 0x6c, 0x6c, 0x54, 0x69,
+	0x74, 0x6c, 0x65, 0x73, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x0e, 0x61, 0x6c, 0x6c, 0x54, 0x69,
+	0x74, 0x6c, 0x65, 0x73, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x22, 0xd8, 0x01, 0x0a, 0x0d, 0x53, 0x75,
+	0x63, 0x63, 0x65, 0x73, 0x73, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x45, 0x0a, 0x11, 0x74,
+	0x69, 0x74, 0x6c, 0x65, 0x5f, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x5f, 0x76, 0x69, 0x65, 0x77,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x74, 0x6c, 0x65, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x56, 0x69, 0x65,
+	0x77, 0x52, 0x0f, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x56, 0x69,
+	0x65, 0x77, 0x12, 0x38, 0x0a, 0x0c, 0x6d, 0x61, 0x6e, 0x67, 0x61, 0x5f, 0x76, 0x69, 0x65, 0x77,
+	0x65, 0x72, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x4d, 0x61, 0x6e, 0x67, 0x61, 0x56, 0x69, 0x65, 0x77, 0x65, 0x72, 0x52,
+	0x0b, 0x6d, 0x61, 0x6e, 0x67, 0x61, 0x56, 0x69, 0x65, 0x77, 0x65, 0x72, 0x12, 0x46, 0x0a, 0x12,
+	0x61, 0x6c, 0x6c, 0x5f, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x73, 0x5f, 0x76, 0x69, 0x65, 0x77, 0x5f,
+	0x76, 0x32, 0x18, 0x19, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x41, 0x6c, 0x6c, 0x54, 0x69, 0x74, 0x6c, 0x65, 0x73, 0x56, 0x69, 0x65,
+	0x77, 0x56, 0x32, 0x52, 0x0f, 0x61, 0x6c, 0x6c, 0x54, 0x69, 0x74, 0x6c, 0x65, 0x73, 0x56, 0x69,
+	0x65, 0x77, 0x56, 0x32, 0x22, 0x3d, 0x0a, 0x08, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x31, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x75, 0x63,
+	0x63, 0x65, 0x73, 0x73, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63,
+	0x65, 0x73, 0x73, 0x42, 0x13, 0x5a, 0x11, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -2271,7 +2392,7 @@ func file_internal_protobuf_response_proto_rawDescGZIP() []byte {
 	return file_internal_protobuf_response_proto_rawDescData
 }
 
-var file_internal_protobuf_response_proto_msgTypes = make([]protoimpl.MessageInfo, 27)
+var file_internal_protobuf_response_proto_msgTypes = make([]protoimpl.MessageInfo, 29)
 var file_internal_protobuf_response_proto_goTypes = []any{
 	(*Banner)(nil),                           // 0: protobuf.Banner
 	(*BannerList)(nil),                       // 1: protobuf.BannerList
@@ -2288,18 +2409,20 @@ var file_internal_protobuf_response_proto_goTypes = []any{
 	(*MangaViewer)(nil),                      // 12: protobuf.MangaViewer
 	(*Title)(nil),                            // 13: protobuf.Title
 	(*TitleDetailView)(nil),                  // 14: protobuf.TitleDetailView
-	(*SuccessResult)(nil),                    // 15: protobuf.SuccessResult
-	(*Response)(nil),                         // 16: protobuf.Response
-	(*AdNetworkList_AdNetwork)(nil),          // 17: protobuf.AdNetworkList.AdNetwork
-	(*AdNetworkList_AdNetwork_Facebook)(nil), // 18: protobuf.AdNetworkList.AdNetwork.Facebook
-	(*AdNetworkList_AdNetwork_Admob)(nil),    // 19: protobuf.AdNetworkList.AdNetwork.Admob
-	(*AdNetworkList_AdNetwork_Mopub)(nil),    // 20: protobuf.AdNetworkList.AdNetwork.Mopub
-	(*AdNetworkList_AdNetwork_Adsense)(nil),  // 21: protobuf.AdNetworkList.AdNetwork.Adsense
-	(*AdNetworkList_AdNetwork_Applovin)(nil), // 22: protobuf.AdNetworkList.AdNetwork.Applovin
-	(*Popup_Button)(nil),                     // 23: protobuf.Popup.Button
-	(*Popup_OSDefault)(nil),                  // 24: protobuf.Popup.OSDefault
-	(*Popup_AppDefault)(nil),                 // 25: protobuf.Popup.AppDefault
-	(*Popup_MovieReward)(nil),                // 26: protobuf.Popup.MovieReward
+	(*AllTitlesGroup)(nil),                   // 15: protobuf.AllTitlesGroup
+	(*AllTitlesViewV2)(nil),                  // 16: protobuf.AllTitlesViewV2
+	(*SuccessResult)(nil),                    // 17: protobuf.SuccessResult
+	(*Response)(nil),                         // 18: protobuf.Response
+	(*AdNetworkList_AdNetwork)(nil),          // 19: protobuf.AdNetworkList.AdNetwork
+	(*AdNetworkList_AdNetwork_Facebook)(nil), // 20: protobuf.AdNetworkList.AdNetwork.Facebook
+	(*AdNetworkList_AdNetwork_Admob)(nil),    // 21: protobuf.AdNetworkList.AdNetwork.Admob
+	(*AdNetworkList_AdNetwork_Mopub)(nil),    // 22: protobuf.AdNetworkList.AdNetwork.Mopub
+	(*AdNetworkList_AdNetwork_Adsense)(nil),  // 23: protobuf.AdNetworkList.AdNetwork.Adsense
+	(*AdNetworkList_AdNetwork_Applovin)(nil), // 24: protobuf.AdNetworkList.AdNetwork.Applovin
+	(*Popup_Button)(nil),                     // 25: protobuf.Popup.Button
+	(*Popup_OSDefault)(nil),                  // 26: protobuf.Popup.OSDefault
+	(*Popup_AppDefault)(nil),                 // 27: protobuf.Popup.AppDefault
+	(*Popup_MovieReward)(nil),                // 28: protobuf.Popup.MovieReward
 }
 var file_internal_protobuf_response_proto_depIdxs = []int32{
 	2,  // 0: protobuf.Banner.action:type_name -> protobuf.TransitionAction
@@ -2307,10 +2430,10 @@ var file_internal_protobuf_response_proto_depIdxs = []int32{
 	3,  // 2: protobuf.ChapterGroup.first_chapter_list:type_name -> protobuf.Chapter
 	3,  // 3: protobuf.ChapterGroup.mid_chapter_list:type_name -> protobuf.Chapter
 	3,  // 4: protobuf.ChapterGroup.last_chapter_list:type_name -> protobuf.Chapter
-	17, // 5: protobuf.AdNetworkList.ad_networks:type_name -> protobuf.AdNetworkList.AdNetwork
-	24, // 6: protobuf.Popup.os_default:type_name -> protobuf.Popup.OSDefault
-	25, // 7: protobuf.Popup.app_default:type_name -> protobuf.Popup.AppDefault
-	26, // 8: protobuf.Popup.movie_reward:type_name -> protobuf.Popup.MovieReward
+	19, // 5: protobuf.AdNetworkList.ad_networks:type_name -> protobuf.AdNetworkList.AdNetwork
+	26, // 6: protobuf.Popup.os_default:type_name -> protobuf.Popup.OSDefault
+	27, // 7: protobuf.Popup.app_default:type_name -> protobuf.Popup.AppDefault
+	28, // 8: protobuf.Popup.movie_reward:type_name -> protobuf.Popup.MovieReward
 	3,  // 9: protobuf.LastPage.current_chapter:type_name -> protobuf.Chapter
 	3,  // 10: protobuf.LastPage.next_chapter:type_name -> protobuf.Chapter
 	5,  // 11: protobuf.LastPage.top_comments:type_name -> protobuf.Comment
@@ -2330,25 +2453,28 @@ var file_internal_protobuf_response_proto_depIdxs = []int32{
 	13, // 25: protobuf.TitleDetailView.recommended_title_list:type_name -> protobuf.Title
 	11, // 26: protobuf.TitleDetailView.sns:type_name -> protobuf.Sns
 	4,  // 27: protobuf.TitleDetailView.chapter_list_group:type_name -> protobuf.ChapterGroup
-	14, // 28: protobuf.SuccessResult.title_detail_view:type_name -> protobuf.TitleDetailView
-	12, // 29: protobuf.SuccessResult.manga_viewer:type_name -> protobuf.MangaViewer
-	15, // 30: protobuf.Response.success:type_name -> protobuf.SuccessResult
-	18, // 31: protobuf.AdNetworkList.AdNetwork.facebook:type_name -> protobuf.AdNetworkList.AdNetwork.Facebook
-	19, // 32: protobuf.AdNetworkList.AdNetwork.admob:type_name -> protobuf.AdNetworkList.AdNetwork.Admob
-	20, // 33: protobuf.AdNetworkList.AdNetwork.mopub:type_name -> protobuf.AdNetworkList.AdNetwork.Mopub
-	21, // 34: protobuf.AdNetworkList.AdNetwork.adsense:type_name -> protobuf.AdNetworkList.AdNetwork.Adsense
-	22, // 35: protobuf.AdNetworkList.AdNetwork.applovin:type_name -> protobuf.AdNetworkList.AdNetwork.Applovin
-	2,  // 36: protobuf.Popup.Button.action:type_name -> protobuf.TransitionAction
-	23, // 37: protobuf.Popup.OSDefault.ok_button:type_name -> protobuf.Popup.Button
-	23, // 38: protobuf.Popup.OSDefault.neutral_button:type_name -> protobuf.Popup.Button
-	23, // 39: protobuf.Popup.OSDefault.cancel_button:type_name -> protobuf.Popup.Button
-	2,  // 40: protobuf.Popup.AppDefault.action:type_name -> protobuf.TransitionAction
-	6,  // 41: protobuf.Popup.MovieReward.advertisement:type_name -> protobuf.AdNetworkList
-	42, // [42:42] is the sub-list for method output_type
-	42, // [42:42] is the sub-list for method input_type
-	42, // [42:42] is the sub-list for extension type_name
-	42, // [42:42] is the sub-list for extension extendee
-	0,  // [0:42] is the sub-list for field type_name
+	13, // 28: protobuf.AllTitlesGroup.titles:type_name -> protobuf.Title
+	15, // 29: protobuf.AllTitlesViewV2.all_titles_group:type_name -> protobuf.AllTitlesGroup
+	14, // 30: protobuf.SuccessResult.title_detail_view:type_name -> protobuf.TitleDetailView
+	12, // 31: protobuf.SuccessResult.manga_viewer:type_name -> protobuf.MangaViewer
+	16, // 32: protobuf.SuccessResult.all_titles_view_v2:type_name -> protobuf.AllTitlesViewV2
+	17, // 33: protobuf.Response.success:type_name -> protobuf.SuccessResult
+	20, // 34: protobuf.AdNetworkList.AdNetwork.facebook:type_name -> protobuf.AdNetworkList.AdNetwork.Facebook
+	21, // 35: protobuf.AdNetworkList.AdNetwork.admob:type_name -> protobuf.AdNetworkList.AdNetwork.Admob
+	22, // 36: protobuf.AdNetworkList.AdNetwork.mopub:type_name -> protobuf.AdNetworkList.AdNetwork.Mopub
+	23, // 37: protobuf.AdNetworkList.AdNetwork.adsense:type_name -> protobuf.AdNetworkList.AdNetwork.Adsense
+	24, // 38: protobuf.AdNetworkList.AdNetwork.applovin:type_name -> protobuf.AdNetworkList.AdNetwork.Applovin
+	2,  // 39: protobuf.Popup.Button.action:type_name -> protobuf.TransitionAction
+	25, // 40: protobuf.Popup.OSDefault.ok_button:type_name -> protobuf.Popup.Button
+	25, // 41: protobuf.Popup.OSDefault.neutral_button:type_name -> protobuf.Popup.Button
+	25, // 42: protobuf.Popup.OSDefault.cancel_button:type_name -> protobuf.Popup.Button
+	2,  // 43: protobuf.Popup.AppDefault.action:type_name -> protobuf.TransitionAction
+	6,  // 44: protobuf.Popup.MovieReward.advertisement:type_name -> protobuf.AdNetworkList
+	45, // [45:45] is the sub-list for method output_type
+	45, // [45:45] is the sub-list for method input_type
+	45, // [45:45] is the sub-list for extension type_name
+	45, // [45:45] is the sub-list for extension extendee
+	0,  // [0:45] is the sub-list for field type_name
 }
 
 func init() { file_internal_protobuf_response_proto_init() }
@@ -2362,7 +2488,7 @@ func file_internal_protobuf_response_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_internal_protobuf_response_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   27,
+			NumMessages:   29,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
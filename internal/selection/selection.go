@@ -0,0 +1,92 @@
+package selection
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"mangarr/internal/domain"
+)
+
+// Strategy decides between releases of a chapter that are equally preferred by group
+type Strategy string
+
+const (
+	// StrategyNewest picks the most recently published release
+	StrategyNewest Strategy = "newest"
+	// StrategyPages picks the release with the most pages
+	StrategyPages Strategy = "pages"
+	// StrategyFirst picks the release listed first by the source
+	StrategyFirst Strategy = "first"
+)
+
+// ParseStrategy parses the strategy name used in the config and flags, an empty name selects StrategyNewest
+func ParseStrategy(name string) (Strategy, error) {
+	switch Strategy(strings.ToLower(strings.TrimSpace(name))) {
+	case "", StrategyNewest:
+		return StrategyNewest, nil
+	case StrategyPages:
+		return StrategyPages, nil
+	case StrategyFirst:
+		return StrategyFirst, nil
+	default:
+		return "", fmt.Errorf("unknown selection strategy %q, must be one of: newest, pages, first", name)
+	}
+}
+
+// Policy selects the release that should be downloaded when a chapter number has multiple releases
+type Policy struct {
+	// PreferredGroups lists group names in order of preference, releases of other groups come last
+	PreferredGroups []string
+	Strategy        Strategy
+}
+
+// New creates a policy from the preferred groups and strategy name
+func New(preferredGroups []string, strategy string) (Policy, error) {
+	s, err := ParseStrategy(strategy)
+	if err != nil {
+		return Policy{}, err
+	}
+
+	return Policy{
+		PreferredGroups: preferredGroups,
+		Strategy:        s,
+	}, nil
+}
+
+// Select returns the release preferred by the policy, it returns false if there are no releases
+func (p Policy) Select(releases []domain.Chapter) (domain.Chapter, bool) {
+	if len(releases) == 0 {
+		return domain.Chapter{}, false
+	}
+
+	sorted := slices.Clone(releases)
+
+	slices.SortStableFunc(sorted, func(a, b domain.Chapter) int {
+		if rankA, rankB := p.groupRank(a.Group), p.groupRank(b.Group); rankA != rankB {
+			return rankA - rankB
+		}
+
+		switch p.Strategy {
+		case StrategyPages:
+			return b.Pages - a.Pages
+		case StrategyFirst:
+			return 0
+		default:
+			return b.PublishedAt.Compare(a.PublishedAt)
+		}
+	})
+
+	return sorted[0], true
+}
+
+// groupRank returns the position of group in the preferred groups, groups that aren't preferred rank last
+func (p Policy) groupRank(group string) int {
+	for i, preferred := range p.PreferredGroups {
+		if strings.EqualFold(strings.TrimSpace(preferred), group) {
+			return i
+		}
+	}
+
+	return len(p.PreferredGroups)
+}
@@ -0,0 +1,89 @@
+package selection
+
+import (
+	"testing"
+	"time"
+
+	"mangarr/internal/domain"
+)
+
+func TestParseStrategy(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Strategy
+		wantErr bool
+	}{
+		{name: "", want: StrategyNewest},
+		{name: "newest", want: StrategyNewest},
+		{name: " Pages ", want: StrategyPages},
+		{name: "FIRST", want: StrategyFirst},
+		{name: "oldest", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseStrategy(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseStrategy(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicySelect(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2024, time.January, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	releases := []domain.Chapter{
+		{ID: "a", Group: "Alpha", Pages: 20, PublishedAt: day(1)},
+		{ID: "b", Group: "Beta", Pages: 30, PublishedAt: day(3)},
+		{ID: "c", Group: "Gamma", Pages: 25, PublishedAt: day(2)},
+		{ID: "d", Group: "Beta", Pages: 10, PublishedAt: day(4)},
+	}
+
+	tests := []struct {
+		name      string
+		preferred []string
+		strategy  Strategy
+		want      string
+	}{
+		{name: "newest", strategy: StrategyNewest, want: "d"},
+		{name: "pages", strategy: StrategyPages, want: "b"},
+		{name: "first", strategy: StrategyFirst, want: "a"},
+		{name: "preferred group wins over newest", preferred: []string{"Gamma"}, strategy: StrategyNewest, want: "c"},
+		{name: "preferred group wins over pages", preferred: []string{"alpha"}, strategy: StrategyPages, want: "a"},
+		{name: "strategy decides within preferred group", preferred: []string{"Beta"}, strategy: StrategyPages, want: "b"},
+		{name: "order of preferred groups", preferred: []string{"Delta", "Gamma", "Alpha"}, strategy: StrategyFirst, want: "c"},
+		{name: "unknown preferred groups fall back to strategy", preferred: []string{"Delta"}, strategy: StrategyNewest, want: "d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Policy{PreferredGroups: tt.preferred, Strategy: tt.strategy}
+
+			got, ok := p.Select(releases)
+			if !ok {
+				t.Fatal("Select returned no release")
+			}
+			if got.ID != tt.want {
+				t.Errorf("Select = %q, want %q", got.ID, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicySelectEmpty(t *testing.T) {
+	if _, ok := (Policy{}).Select(nil); ok {
+		t.Error("Select of no releases should return false")
+	}
+}
+
+func TestNewInvalidStrategy(t *testing.T) {
+	if _, err := New(nil, "random"); err == nil {
+		t.Error("New with an unknown strategy should fail")
+	}
+}
@@ -1,10 +1,14 @@
 package sharedhttp
 
 import (
+	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/avast/retry-go"
@@ -42,6 +46,9 @@ func CheckStatusCode(statusCode int) error {
 	case http.StatusNotFound:
 		return fmt.Errorf("image not found - retrying: status code %d", statusCode)
 
+	case http.StatusTooManyRequests:
+		return fmt.Errorf("rate limited - retrying: status code %d", statusCode)
+
 	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout, http.StatusInternalServerError:
 		return fmt.Errorf("server error encountered while downloading image: status code %d - retrying", statusCode)
 
@@ -64,3 +71,114 @@ func ExecRequest(client http.Client, req *http.Request) (http.Response, error) {
 
 	return *resp, nil
 }
+
+// RateLimitError is returned for responses with status 429, After holds how long the server asked clients to wait
+type RateLimitError struct {
+	After time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	if e.After > 0 {
+		return fmt.Sprintf("rate limited, retrying after %s: status code %d", e.After, http.StatusTooManyRequests)
+	}
+
+	return fmt.Sprintf("rate limited - retrying: status code %d", http.StatusTooManyRequests)
+}
+
+// CheckResponse checks the status code of the response like CheckStatusCode, responses with status 429 return a
+// RateLimitError holding how long to wait before retrying
+func CheckResponse(resp *http.Response) error {
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return &RateLimitError{After: RetryAfter(resp)}
+	}
+
+	return CheckStatusCode(resp.StatusCode)
+}
+
+// RetryAfter returns how long the response asks clients to wait before sending further requests, it reads the
+// Retry-After header in seconds or as a date and the X-RateLimit-Retry-After header as a unix timestamp
+func RetryAfter(resp *http.Response) time.Duration {
+	var after time.Duration
+
+	if value := resp.Header.Get("Retry-After"); len(value) != 0 {
+		if seconds, err := strconv.Atoi(value); err == nil {
+			after = time.Duration(seconds) * time.Second
+		} else if date, err := http.ParseTime(value); err == nil {
+			after = time.Until(date)
+		}
+	}
+
+	if value := resp.Header.Get("X-RateLimit-Retry-After"); len(value) != 0 {
+		if timestamp, err := strconv.ParseInt(value, 10, 64); err == nil {
+			after = max(after, time.Until(time.Unix(timestamp, 0)))
+		}
+	}
+
+	return max(after, 0)
+}
+
+// RetryAfterDelay waits as long as a RateLimitError asks for and backs off otherwise, it is meant to be used with
+// retry.DelayType
+func RetryAfterDelay(n uint, err error, config *retry.Config) time.Duration {
+	var rateLimitErr *RateLimitError
+	if errors.As(err, &rateLimitErr) && rateLimitErr.After > 0 {
+		return rateLimitErr.After
+	}
+
+	return retry.CombineDelay(retry.BackOffDelay, retry.RandomDelay)(n, err, config)
+}
+
+// Limiter spaces out the requests to an api and holds them back while the api asks clients to wait
+type Limiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// NewLimiter creates a limiter allowing the given number of requests per period
+func NewLimiter(requests int, per time.Duration) *Limiter {
+	return &Limiter{
+		interval: per / time.Duration(requests),
+	}
+}
+
+// Wait blocks until the next request may be sent or the context is done
+func (l *Limiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+	l.next = at.Add(l.interval)
+	l.mu.Unlock()
+
+	timer := time.NewTimer(time.Until(at))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Update holds back further requests if the response is rate limited or says no requests are remaining
+func (l *Limiter) Update(resp *http.Response) {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		return
+	}
+
+	after := RetryAfter(resp)
+	if after <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if until := time.Now().Add(after); until.After(l.next) {
+		l.next = until
+	}
+}
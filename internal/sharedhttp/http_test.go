@@ -0,0 +1,127 @@
+package sharedhttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/avast/retry-go"
+)
+
+func response(status int, headers map[string]string) *http.Response {
+	resp := &http.Response{StatusCode: status, Header: make(http.Header)}
+	for key, value := range headers {
+		resp.Header.Set(key, value)
+	}
+	return resp
+}
+
+// near reports whether got is within two seconds of want, dates and timestamps in headers are truncated to seconds
+func near(got, want time.Duration) bool {
+	diff := got - want
+	return diff > -2*time.Second && diff < 2*time.Second
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    time.Duration
+	}{
+		{name: "none", want: 0},
+		{name: "seconds", headers: map[string]string{"Retry-After": "30"}, want: 30 * time.Second},
+		{name: "http date", headers: map[string]string{"Retry-After": now.Add(2 * time.Minute).UTC().Format(http.TimeFormat)}, want: 2 * time.Minute},
+		{name: "http date in the past", headers: map[string]string{"Retry-After": now.Add(-time.Minute).UTC().Format(http.TimeFormat)}, want: 0},
+		{name: "invalid", headers: map[string]string{"Retry-After": "soon"}, want: 0},
+		{name: "rate limit timestamp", headers: map[string]string{"X-RateLimit-Retry-After": strconv.FormatInt(now.Add(45*time.Second).Unix(), 10)}, want: 45 * time.Second},
+		{
+			name: "longer of both",
+			headers: map[string]string{
+				"Retry-After":             "10",
+				"X-RateLimit-Retry-After": strconv.FormatInt(now.Add(time.Minute).Unix(), 10),
+			},
+			want: time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RetryAfter(response(http.StatusTooManyRequests, tt.headers))
+			if !near(got, tt.want) {
+				t.Errorf("RetryAfter = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckResponse(t *testing.T) {
+	err := CheckResponse(response(http.StatusTooManyRequests, map[string]string{"Retry-After": "7"}))
+
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		t.Fatalf("CheckResponse = %v, want RateLimitError", err)
+	}
+	if rateLimitErr.After != 7*time.Second {
+		t.Errorf("After = %s, want 7s", rateLimitErr.After)
+	}
+
+	if err := CheckResponse(response(http.StatusOK, nil)); err != nil {
+		t.Errorf("CheckResponse of 200 = %v", err)
+	}
+}
+
+func TestRetryAfterDelay(t *testing.T) {
+	config := &retry.Config{}
+	retry.Delay(100 * time.Millisecond)(config)
+	retry.MaxJitter(10 * time.Millisecond)(config)
+
+	if got := RetryAfterDelay(1, &RateLimitError{After: 12 * time.Second}, config); got != 12*time.Second {
+		t.Errorf("delay of rate limit error = %s, want 12s", got)
+	}
+
+	if got := RetryAfterDelay(1, errors.New("server error"), config); got <= 0 || got >= 12*time.Second {
+		t.Errorf("delay of other error = %s, want backoff", got)
+	}
+}
+
+func TestLimiterUpdate(t *testing.T) {
+	l := NewLimiter(1000, time.Second)
+
+	l.Update(response(http.StatusOK, map[string]string{"Retry-After": "60"}))
+	if !l.next.IsZero() {
+		t.Error("successful responses with requests remaining should not hold back requests")
+	}
+
+	l.Update(response(http.StatusTooManyRequests, map[string]string{"Retry-After": "60"}))
+	if wait := time.Until(l.next); !near(wait, time.Minute) {
+		t.Errorf("held back for %s, want 1m", wait)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := l.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait while held back = %v, want deadline exceeded", err)
+	}
+}
+
+func TestLimiterSpacesRequests(t *testing.T) {
+	l := NewLimiter(20, time.Second)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the first request goes out right away, the others 50ms apart
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("3 requests took %s, want at least 100ms", elapsed)
+	}
+}
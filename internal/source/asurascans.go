@@ -4,35 +4,54 @@ import (
 	"context"
 	"fmt"
 	"net/url"
-	"strconv"
+	"regexp"
 	"strings"
 	"time"
 
 	"mangarr/internal/domain"
 	"mangarr/internal/sanitize"
 
+	"github.com/PuerkitoBio/goquery"
 	"github.com/gocolly/colly"
-	"github.com/gocolly/colly/extensions"
 )
 
-const asurascansURL = "https://asuracomic.net/series/"
+const (
+	asurascansURL       = "https://asuracomic.net/series/"
+	asurascansSearchURL = "https://asuracomic.net/series"
+)
+
+// asurascansLockedPattern matches the texts shown on early access chapters that have to be unlocked with coins
+var asurascansLockedPattern = regexp.MustCompile(`(?i)\b(premium|early access|locked|unlock|coins?)\b`)
+
+// asurascansPaywallSelector matches the buttons of chapter pages, the paywall of early access chapters shows a button
+// to unlock the chapter instead of its images
+const asurascansPaywallSelector = "main button"
+
+// asurascansUnlockPattern matches the label of the paywall button like "Unlock Chapter" or "Unlock with 50 coins"
+var asurascansUnlockPattern = regexp.MustCompile(`(?i)^\s*unlock\s+(chapter|with|for|now)\b`)
 
 type asurascans struct {
-	MangaURL  string
-	Collector colly.Collector
+	MangaURL string
 }
 
-func NewAsurascans(mangaURL string) domain.Source {
-	collector := colly.NewCollector(
-		colly.AllowURLRevisit(),
-	)
-	extensions.RandomUserAgent(collector)
-
-	collector.SetRequestTimeout(120 * time.Second)
+func init() {
+	Register(Definition{
+		Key:  "asurascans",
+		Name: "Asura Scans",
+		Fields: []Field{
+			{Name: InputManga, Required: true, Help: "URL of the series on Asura Scans", Example: "https://asuracomic.net/series/solo-max-level-newbie-31f980f5"},
+		},
+		Languages: []string{"en"},
+		Manhwa:    true,
+		New: func(in Input) domain.Source {
+			return NewAsurascans(in.Manga)
+		},
+	})
+}
 
+func NewAsurascans(mangaURL string) domain.Source {
 	return &asurascans{
-		MangaURL:  mangaURL,
-		Collector: *collector,
+		MangaURL: mangaURL,
 	}
 }
 
@@ -52,33 +71,89 @@ func (a *asurascans) ValidateInput() error {
 	return nil
 }
 
-func (a *asurascans) GetManga(_ context.Context) (domain.Manga, error) {
+func (a *asurascans) GetManga(ctx context.Context) (domain.Manga, error) {
 	var manga domain.Manga
-	manga.Chapters = make(map[float32]domain.Chapter)
+	manga.Chapters = make(map[string][]domain.Chapter)
 
-	c := a.Collector.Clone()
+	c := newCollector(ctx)
+
+	manga.Metadata = domain.Metadata{
+		ReadingDirection: domain.DirectionVertical,
+		SourceURL:        a.MangaURL,
+	}
 
 	c.OnHTML("span.text-xl.font-bold", func(e *colly.HTMLElement) {
 		manga.Title = sanitize.Filename(e.Text)
 	})
 
+	c.OnHTML("img[alt='poster']", func(e *colly.HTMLElement) {
+		manga.Metadata.CoverURL = e.Attr("src")
+	})
+
+	c.OnHTML("span.font-medium.text-sm", func(e *colly.HTMLElement) {
+		manga.Metadata.Synopsis = strings.TrimSpace(e.Text)
+	})
+
+	c.OnHTML("div.flex.flex-row.flex-wrap.gap-3 button", func(e *colly.HTMLElement) {
+		manga.Metadata.Genres = append(manga.Metadata.Genres, strings.TrimSpace(e.Text))
+	})
+
+	// the info table consists of a label heading followed by a value heading
+	c.OnHTML("h3", func(e *colly.HTMLElement) {
+		value := strings.TrimSpace(e.DOM.Next().Text())
+		if len(value) == 0 || value == "_" {
+			return
+		}
+
+		switch strings.TrimSpace(e.Text) {
+		case "Status":
+			manga.Metadata.Status = domain.ParsePublicationStatus(value)
+		case "Author":
+			manga.Metadata.Authors = append(manga.Metadata.Authors, value)
+		case "Artist":
+			manga.Metadata.Artists = append(manga.Metadata.Artists, value)
+		}
+	})
+
 	c.OnHTML(".pl-4.pr-2.pb-4 a", func(e *colly.HTMLElement) {
-		chapterNum, chapterTitle, err := a.splitChapterInfo(e.Text)
+		// the first heading holds the chapter info, the one after it the release date
+		info := e.DOM.Find("h3").First().Text()
+		if len(info) == 0 {
+			info = e.Text
+		}
+
+		chapterNum, chapterTitle, err := a.splitChapterInfo(info)
 		if err != nil {
 			return
 		}
 
 		chapterURL := e.Attr("href")
 
-		manga.Chapters[chapterNum] = domain.Chapter{
-			URL:      chapterURL,
-			Number:   chapterNum,
-			Title:    chapterTitle,
-			IsManhwa: true,
+		release := domain.Chapter{
+			URL:         chapterURL,
+			Number:      chapterNum,
+			Title:       chapterTitle,
+			PublishedAt: parseReleaseDate(e.ChildText("h3.text-xs"), "January 2 2006"),
+			Group:       "Asura Scans",
+			Language:    "en",
+			IsManhwa:    true,
+		}
+
+		// early access chapters are listed with a lock and show a countdown or the date they become free
+		if item := a.chapterItem(e.DOM); a.isLocked(item) {
+			now := time.Now()
+
+			release.Locked = true
+			release.AvailableFrom = parseRelativeDate(a.statusText(item), now)
+			if release.AvailableFrom.IsZero() && release.PublishedAt.After(now) {
+				release.AvailableFrom = release.PublishedAt
+			}
 		}
+
+		manga.AddChapter(release)
 	})
 
-	err := c.Visit(a.MangaURL)
+	err := visit(ctx, c, a.MangaURL)
 	if err != nil {
 		return domain.Manga{}, err
 	}
@@ -98,10 +173,15 @@ func (a *asurascans) GetChapters(_ context.Context, _ domain.Manga) error {
 	return nil
 }
 
-func (a *asurascans) GetImageURLs(_ context.Context, chapter *domain.Chapter) error {
-	c := a.Collector.Clone()
+func (a *asurascans) GetImageURLs(ctx context.Context, chapter *domain.Chapter) error {
+	if err := chapter.CheckAvailable(time.Now()); err != nil {
+		return err
+	}
+
+	c := newCollector(ctx)
 
 	var imageInfos []domain.ImageInfo
+	var locked bool
 
 	c.OnHTML(".w-full.mx-auto img", func(e *colly.HTMLElement) {
 		imgURL := e.Attr("src")
@@ -110,29 +190,124 @@ func (a *asurascans) GetImageURLs(_ context.Context, chapter *domain.Chapter) er
 		}
 	})
 
-	err := c.Visit(asurascansURL + chapter.URL)
+	c.OnHTML(asurascansPaywallSelector, func(e *colly.HTMLElement) {
+		if a.isPaywall(e.DOM) {
+			locked = true
+		}
+	})
+
+	err := visit(ctx, c, asurascansURL+chapter.URL)
 	if err != nil {
 		return err
 	}
 
+	// chapters that were unlocked for the listing may still show the paywall for a while
+	if len(imageInfos) == 0 && locked {
+		return fmt.Errorf("chapter %s is locked: %w", chapter.Number, domain.ErrChapterUnavailable)
+	}
+
 	if len(imageInfos) == 0 {
-		return fmt.Errorf("failed to get image urls for chapter number: %g", chapter.Number)
+		return fmt.Errorf("failed to get image urls for chapter number: %s", chapter.Number)
 	}
 
 	chapter.ImageInfo = imageInfos
 	return nil
 }
 
-func (a *asurascans) splitChapterInfo(input string) (float32, string, error) {
-	parts := strings.SplitN(input, " ", 3)
-	chapterNumberStr := parts[1]
-	chapterNumber, err := strconv.ParseFloat(chapterNumberStr, 32)
+func (a *asurascans) Search(ctx context.Context, query string) ([]domain.SearchResult, error) {
+	c := newCollector(ctx)
+
+	var results []domain.SearchResult
+
+	c.OnHTML("div.grid a[href^='series/']", func(e *colly.HTMLElement) {
+		title := strings.TrimSpace(e.ChildText("span.block.font-bold"))
+		if len(title) == 0 {
+			return
+		}
+
+		mangaURL := asurascansURL + strings.TrimPrefix(e.Attr("href"), "series/")
+
+		results = append(results, domain.SearchResult{
+			Title:    title,
+			ID:       mangaURL,
+			URL:      mangaURL,
+			CoverURL: e.ChildAttr("img", "src"),
+			Language: "en",
+		})
+	})
+
+	params := url.Values{
+		"page": []string{"1"},
+		"name": []string{query},
+	}
+
+	err := visit(ctx, c, asurascansSearchURL+"?"+params.Encode())
 	if err != nil {
-		return 0, "", err
+		return nil, err
 	}
 
+	return results, nil
+}
+
+func (a *asurascans) splitChapterInfo(input string) (domain.ChapterNumber, string, error) {
+	parts := strings.SplitN(strings.TrimSpace(input), " ", 3)
+	if len(parts) < 2 {
+		return domain.ChapterNumber{}, "", fmt.Errorf("failed to get chapter number from: %s", input)
+	}
+
+	chapterNumber := domain.ParseChapterNumber(parts[1])
+
 	chapterTitle := strings.TrimSpace(strings.Join(parts[2:], " "))
 	chapterTitle = sanitize.Filename(chapterTitle)
 
-	return float32(chapterNumber), chapterTitle, nil
+	return chapterNumber, chapterTitle, nil
+}
+
+// chapterItem returns the list item of the chapter link, the lock of early access chapters may be placed next to the
+// link in the list item
+func (a *asurascans) chapterItem(link *goquery.Selection) *goquery.Selection {
+	item := link.Parent()
+	if item.Find("a").Length() > 1 {
+		return link
+	}
+
+	return item
+}
+
+// isLocked reports whether the chapter in the list item is an early access chapter
+func (a *asurascans) isLocked(item *goquery.Selection) bool {
+	if asurascansLockedPattern.MatchString(a.statusText(item)) {
+		return true
+	}
+
+	return item.Find("svg").FilterFunction(func(_ int, svg *goquery.Selection) bool {
+		class, _ := svg.Attr("class")
+		return strings.Contains(strings.ToLower(class), "lock")
+	}).Length() != 0
+}
+
+// statusText returns the texts of the list item besides the chapter info, so chapter titles aren't mistaken for the
+// labels of locked chapters
+func (a *asurascans) statusText(item *goquery.Selection) string {
+	var texts []string
+
+	item.Find("h3, span, p").Each(func(i int, e *goquery.Selection) {
+		// the first heading holds the chapter info, its children are skipped as well
+		if i == 0 || e.ParentsFiltered("h3").Length() != 0 {
+			return
+		}
+		texts = append(texts, strings.TrimSpace(e.Text()))
+	})
+
+	return strings.Join(texts, " ")
+}
+
+// isPaywall reports whether the button offers to unlock the chapter, the navigation advertising the premium membership
+// on every page is left out
+func (a *asurascans) isPaywall(button *goquery.Selection) bool {
+	if button.ParentsFiltered("nav, header, footer").Length() != 0 {
+		return false
+	}
+
+	return asurascansUnlockPattern.MatchString(button.Text())
 }
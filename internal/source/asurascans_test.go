@@ -0,0 +1,145 @@
+package source
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func asurascansTestDocument(t *testing.T, name string) *goquery.Document {
+	t.Helper()
+
+	page, err := os.Open(filepath.Join("testdata", "asurascans", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer page.Close()
+
+	doc, err := goquery.NewDocumentFromReader(page)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return doc
+}
+
+func TestAsurascansChapterList(t *testing.T) {
+	doc := asurascansTestDocument(t, "series.html")
+	a := &asurascans{}
+
+	tests := map[string]struct {
+		locked bool
+		status string
+	}{
+		"solo-max-level-newbie-31f980f5/chapter/203": {locked: true, status: "Public in 3 days"},
+		"solo-max-level-newbie-31f980f5/chapter/202": {locked: true, status: "Early Access"},
+		// the title of the chapter mustn't be mistaken for the label of a locked chapter
+		"solo-max-level-newbie-31f980f5/chapter/201": {locked: false, status: "March 1st 2025"},
+		"solo-max-level-newbie-31f980f5/chapter/200": {locked: false, status: "February 22nd 2025"},
+	}
+
+	links := doc.Find(".pl-4.pr-2.pb-4 a")
+	if links.Length() != len(tests) {
+		t.Fatalf("chapter links = %d, want %d", links.Length(), len(tests))
+	}
+
+	links.Each(func(_ int, link *goquery.Selection) {
+		href, _ := link.Attr("href")
+
+		tt, ok := tests[href]
+		if !ok {
+			t.Errorf("unexpected chapter link %q", href)
+			return
+		}
+
+		item := a.chapterItem(link)
+		if got := a.statusText(item); got != tt.status {
+			t.Errorf("%s: statusText = %q, want %q", href, got, tt.status)
+		}
+		if got := a.isLocked(item); got != tt.locked {
+			t.Errorf("%s: isLocked = %v, want %v", href, got, tt.locked)
+		}
+	})
+}
+
+func TestAsurascansGetManga(t *testing.T) {
+	server := httptest.NewServer(http.FileServer(http.Dir(filepath.Join("testdata", "asurascans"))))
+	defer server.Close()
+
+	a := &asurascans{MangaURL: server.URL + "/series.html"}
+
+	manga, err := a.GetManga(context.Background())
+	if err != nil {
+		t.Fatalf("GetManga: %v", err)
+	}
+
+	if manga.Title != "Solo Max-Level Newbie" {
+		t.Errorf("Title = %q, want %q", manga.Title, "Solo Max-Level Newbie")
+	}
+	if len(manga.Chapters) != 4 {
+		t.Fatalf("chapters = %d, want 4", len(manga.Chapters))
+	}
+
+	now := time.Now()
+
+	countdown := manga.Chapters["203"][0]
+	if !countdown.Locked || countdown.Available(now) {
+		t.Errorf("chapter 203 should be locked, got %+v", countdown)
+	}
+	if until := time.Until(countdown.AvailableFrom); until < 71*time.Hour || until > 73*time.Hour {
+		t.Errorf("chapter 203 is available from %s, want in 3 days", countdown.AvailableFrom)
+	}
+	if !countdown.Available(now.Add(4 * 24 * time.Hour)) {
+		t.Error("chapter 203 should be available once the countdown is over")
+	}
+
+	earlyAccess := manga.Chapters["202"][0]
+	if !earlyAccess.Locked || !earlyAccess.AvailableFrom.IsZero() || earlyAccess.Available(now) {
+		t.Errorf("chapter 202 should be locked without a date, got %+v", earlyAccess)
+	}
+
+	for _, key := range []string{"201", "200"} {
+		if release := manga.Chapters[key][0]; release.Locked || !release.Available(now) {
+			t.Errorf("chapter %s should be available, got %+v", key, release)
+		}
+	}
+
+	latest, ok := manga.LatestAvailable(now)
+	if !ok || latest.String() != "201" {
+		t.Errorf("LatestAvailable = %s, %v, want 201", latest, ok)
+	}
+}
+
+func TestAsurascansIsPaywall(t *testing.T) {
+	a := &asurascans{}
+
+	tests := []struct {
+		page string
+		want bool
+	}{
+		// the navigation and the promo texts advertising premium and coins are shown on every chapter
+		{page: "chapter.html", want: false},
+		{page: "chapter_locked.html", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.page, func(t *testing.T) {
+			doc := asurascansTestDocument(t, tt.page)
+
+			var got bool
+			doc.Find(asurascansPaywallSelector).Each(func(_ int, button *goquery.Selection) {
+				got = got || a.isPaywall(button)
+			})
+
+			if got != tt.want {
+				t.Errorf("paywall = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
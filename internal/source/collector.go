@@ -0,0 +1,66 @@
+package source
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"mangarr/internal/sharedhttp"
+
+	"github.com/gocolly/colly"
+	"github.com/gocolly/colly/extensions"
+)
+
+// contextTransport binds every request to a context, so in-flight requests abort once it is done
+type contextTransport struct {
+	ctx  context.Context
+	base http.RoundTripper
+}
+
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.base.RoundTrip(req.WithContext(t.ctx))
+}
+
+// newCollector creates a collector for a single operation whose requests honor the deadline and cancellation of ctx
+func newCollector(ctx context.Context) *colly.Collector {
+	collector := colly.NewCollector(
+		colly.AllowURLRevisit(),
+	)
+	extensions.RandomUserAgent(collector)
+
+	collector.SetRequestTimeout(120 * time.Second)
+	collector.WithTransport(&contextTransport{
+		ctx:  ctx,
+		base: sharedhttp.Transport,
+	})
+
+	return collector
+}
+
+// visit visits path with the collector and reports the context error if ctx ended before or during the visit
+func visit(ctx context.Context, c *colly.Collector, path string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	err := c.Visit(path)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+
+	return err
+}
+
+// post posts data to path with the collector and reports the context error if ctx ended before or during the request
+func post(ctx context.Context, c *colly.Collector, path string, data map[string]string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	err := c.Post(path, data)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+
+	return err
+}
@@ -0,0 +1,418 @@
+package source
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"mangarr/internal/domain"
+	"mangarr/internal/sanitize"
+	"mangarr/internal/sharedhttp"
+
+	"github.com/avast/retry-go"
+)
+
+const (
+	comickURL         = "https://api.comick.io"
+	comickSiteURL     = "https://comick.io"
+	comickImagesURL   = "https://meo.comick.pictures"
+	comickLimit       = 300
+	comickSearchLimit = 20
+)
+
+var comickSlug = regexp.MustCompile(`^[a-z0-9-]+$`)
+
+type comick struct {
+	Slug string
+	// HID identifies the comic in the api, the chapters are listed by it
+	HID      string
+	Group    string
+	Language string
+	Client   *http.Client
+}
+
+type comickComic struct {
+	Comic struct {
+		HID      string `json:"hid"`
+		Slug     string `json:"slug"`
+		Title    string `json:"title"`
+		Desc     string `json:"desc"`
+		Status   int    `json:"status"`
+		Country  string `json:"country"`
+		MdTitles []struct {
+			Title string `json:"title"`
+		} `json:"md_titles"`
+		MdCovers []struct {
+			B2Key string `json:"b2key"`
+		} `json:"md_covers"`
+		MdComicMdGenres []struct {
+			MdGenres struct {
+				Name string `json:"name"`
+			} `json:"md_genres"`
+		} `json:"md_comic_md_genres"`
+	} `json:"comic"`
+	Authors []struct {
+		Name string `json:"name"`
+	} `json:"authors"`
+	Artists []struct {
+		Name string `json:"name"`
+	} `json:"artists"`
+}
+
+type comickChapters struct {
+	Chapters []struct {
+		HID       string    `json:"hid"`
+		Chap      *string   `json:"chap"`
+		Vol       *string   `json:"vol"`
+		Title     *string   `json:"title"`
+		Lang      string    `json:"lang"`
+		CreatedAt time.Time `json:"created_at"`
+		GroupName []string  `json:"group_name"`
+	} `json:"chapters"`
+	Total int `json:"total"`
+}
+
+type comickChapter struct {
+	Chapter struct {
+		MdImages []struct {
+			B2Key string  `json:"b2key"`
+			W     float64 `json:"w"`
+			H     float64 `json:"h"`
+		} `json:"md_images"`
+	} `json:"chapter"`
+}
+
+type comickSearch []struct {
+	HID      string `json:"hid"`
+	Slug     string `json:"slug"`
+	Title    string `json:"title"`
+	MdCovers []struct {
+		B2Key string `json:"b2key"`
+	} `json:"md_covers"`
+}
+
+func init() {
+	Register(Definition{
+		Key:  "comick",
+		Name: "Comick",
+		Fields: []Field{
+			{Name: InputManga, Required: true, Help: "Slug or URL of the comic on Comick", Example: "https://comick.io/comic/00-solo-leveling"},
+			{Name: InputGroup, Help: "Name of the scanlation group, chapters of all groups are used if empty", Example: "Asura Scans"},
+			{Name: InputLanguage, Help: `Language code of the chapters, defaults to "en"`, Example: "en"},
+		},
+		New: func(in Input) domain.Source {
+			return NewComick(in.Manga, in.Group, in.Language)
+		},
+	})
+}
+
+func NewComick(manga, group, language string) domain.Source {
+	client := http.Client{
+		Timeout:   60 * time.Second,
+		Transport: sharedhttp.Transport,
+	}
+
+	return &comick{
+		Slug:     manga,
+		Group:    group,
+		Language: language,
+		Client:   &client,
+	}
+}
+
+func (c *comick) String() string {
+	return "Comick"
+}
+
+func (c *comick) ValidateInput() error {
+	// urls of the comic or one of its chapters are reduced to the slug
+	if strings.Contains(c.Slug, "/") {
+		u, err := url.Parse(c.Slug)
+		if err != nil {
+			return err
+		}
+
+		if !strings.Contains(u.Hostname(), "comick") {
+			return fmt.Errorf("the url for comick must be on comick")
+		}
+
+		segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+		if len(segments) < 2 || segments[0] != "comic" {
+			return fmt.Errorf("the url for comick must point to a comic, e.g. %s/comic/<slug>", comickSiteURL)
+		}
+
+		c.Slug = segments[1]
+	}
+
+	if !comickSlug.MatchString(c.Slug) {
+		return fmt.Errorf("invalid comick slug: %s", c.Slug)
+	}
+
+	if len(c.Language) == 0 {
+		c.Language = "en"
+	}
+
+	return nil
+}
+
+func (c *comick) GetManga(ctx context.Context) (domain.Manga, error) {
+	var comicResp comickComic
+
+	path, err := url.JoinPath(comickURL, "comic", c.Slug)
+	if err != nil {
+		return domain.Manga{}, err
+	}
+
+	if err := c.get(ctx, path+"/", &comicResp); err != nil {
+		return domain.Manga{}, err
+	}
+
+	comic := comicResp.Comic
+	if len(comic.HID) == 0 || len(comic.Title) == 0 {
+		return domain.Manga{}, fmt.Errorf("failed to get manga for slug: %s", c.Slug)
+	}
+
+	metadata := domain.Metadata{
+		Synopsis:  strings.TrimSpace(comic.Desc),
+		Status:    comickStatus(comic.Status),
+		SourceURL: comickSiteURL + "/comic/" + comic.Slug,
+	}
+
+	for _, title := range comic.MdTitles {
+		metadata.AltTitles = appendValue(metadata.AltTitles, title.Title)
+	}
+
+	for _, genre := range comic.MdComicMdGenres {
+		metadata.Genres = appendValue(metadata.Genres, genre.MdGenres.Name)
+	}
+
+	for _, author := range comicResp.Authors {
+		metadata.Authors = appendValue(metadata.Authors, author.Name)
+	}
+
+	for _, artist := range comicResp.Artists {
+		metadata.Artists = appendValue(metadata.Artists, artist.Name)
+	}
+
+	if len(comic.MdCovers) != 0 {
+		metadata.CoverURL = comickImagesURL + "/" + comic.MdCovers[0].B2Key
+	}
+
+	switch comic.Country {
+	case "kr", "cn":
+		metadata.ReadingDirection = domain.DirectionVertical
+	case "jp":
+		metadata.ReadingDirection = domain.DirectionRightToLeft
+	}
+
+	c.HID = comic.HID
+
+	return domain.Manga{
+		URL:      comickSiteURL + "/comic/" + comic.Slug,
+		Title:    sanitize.Filename(comic.Title),
+		Metadata: metadata,
+		Chapters: make(map[string][]domain.Chapter),
+	}, nil
+}
+
+func (c *comick) GetChapters(ctx context.Context, manga domain.Manga) error {
+	var chapterCount int
+
+	isManhwa := manga.Metadata.ReadingDirection == domain.DirectionVertical
+
+	for page := 1; ; page++ {
+		var chapterResp comickChapters
+
+		params := url.Values{
+			"lang":       []string{c.Language},
+			"limit":      []string{fmt.Sprintf("%d", comickLimit)},
+			"page":       []string{fmt.Sprintf("%d", page)},
+			"chap-order": []string{"1"},
+		}
+
+		path, err := url.JoinPath(comickURL, "comic", c.HID, "chapters")
+		if err != nil {
+			return err
+		}
+
+		if err := c.get(ctx, path+"?"+params.Encode(), &chapterResp); err != nil {
+			return err
+		}
+
+		for _, data := range chapterResp.Chapters {
+			if len(c.Group) != 0 && !comickHasGroup(data.GroupName, c.Group) {
+				continue
+			}
+
+			// chapters without a number are oneshots
+			label := "Oneshot"
+			if data.Chap != nil && len(*data.Chap) != 0 {
+				label = *data.Chap
+			}
+
+			var title string
+			if data.Title != nil {
+				title = *data.Title
+			}
+
+			var volume string
+			if data.Vol != nil {
+				volume = *data.Vol
+			}
+
+			manga.AddChapter(domain.Chapter{
+				ID:          data.HID,
+				URL:         comickSiteURL + "/comic/" + c.Slug + "/" + data.HID,
+				Number:      domain.ParseChapterNumber(label),
+				Volume:      volume,
+				Title:       sanitize.Filename(title),
+				PublishedAt: data.CreatedAt,
+				Group:       strings.Join(data.GroupName, ", "),
+				Language:    data.Lang,
+				IsManhwa:    isManhwa,
+			})
+		}
+
+		chapterCount += len(chapterResp.Chapters)
+
+		if len(chapterResp.Chapters) == 0 || chapterCount >= chapterResp.Total {
+			break
+		}
+	}
+
+	if len(manga.Chapters) == 0 {
+		return fmt.Errorf("failed to get chapters for slug: %s", c.Slug)
+	}
+
+	return nil
+}
+
+func (c *comick) GetImageURLs(ctx context.Context, chapter *domain.Chapter) error {
+	var chapterResp comickChapter
+	var imageInfos []domain.ImageInfo
+
+	path, err := url.JoinPath(comickURL, "chapter", chapter.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := c.get(ctx, path+"/", &chapterResp); err != nil {
+		return err
+	}
+
+	for _, image := range chapterResp.Chapter.MdImages {
+		imageInfos = append(imageInfos, domain.ImageInfo{
+			ImageURL: comickImagesURL + "/" + image.B2Key,
+			Width:    image.W,
+			Height:   image.H,
+		})
+	}
+
+	if len(imageInfos) == 0 {
+		return fmt.Errorf("failed to get image urls for chapter id: %s", chapter.ID)
+	}
+
+	chapter.ImageInfo = imageInfos
+	return nil
+}
+
+func (c *comick) Search(ctx context.Context, query string) ([]domain.SearchResult, error) {
+	var searchResp comickSearch
+
+	params := url.Values{
+		"q":     []string{query},
+		"limit": []string{fmt.Sprintf("%d", comickSearchLimit)},
+		"type":  []string{"comic"},
+	}
+
+	path, err := url.JoinPath(comickURL, "v1.0", "search")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.get(ctx, path+"?"+params.Encode(), &searchResp); err != nil {
+		return nil, err
+	}
+
+	results := make([]domain.SearchResult, 0, len(searchResp))
+
+	for _, data := range searchResp {
+		result := domain.SearchResult{
+			Title: data.Title,
+			ID:    data.Slug,
+			URL:   comickSiteURL + "/comic/" + data.Slug,
+		}
+
+		if len(data.MdCovers) != 0 {
+			result.CoverURL = comickImagesURL + "/" + data.MdCovers[0].B2Key
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// get requests the url from the api and decodes the json response into v
+func (c *comick) get(ctx context.Context, u string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "mangarr")
+
+	return retry.Do(func() error {
+		resp, err := sharedhttp.ExecRequest(*c.Client, req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		buf := bufio.NewReader(resp.Body)
+
+		err = json.NewDecoder(buf).Decode(v)
+		if err != nil {
+			return retry.Unrecoverable(err)
+		}
+
+		return nil
+	},
+		retry.Delay(time.Second*3),
+		retry.Attempts(3),
+		retry.MaxJitter(time.Second*1),
+	)
+}
+
+// comickHasGroup reports whether group is one of the groups that released a chapter
+func comickHasGroup(groups []string, group string) bool {
+	for _, name := range groups {
+		if strings.EqualFold(strings.TrimSpace(name), strings.TrimSpace(group)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// comickStatus maps the numeric status of a comic to a PublicationStatus
+func comickStatus(status int) domain.PublicationStatus {
+	switch status {
+	case 1:
+		return domain.StatusOngoing
+	case 2:
+		return domain.StatusCompleted
+	case 3:
+		return domain.StatusCancelled
+	case 4:
+		return domain.StatusHiatus
+	default:
+		return domain.StatusUnknown
+	}
+}
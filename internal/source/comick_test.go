@@ -0,0 +1,176 @@
+package source
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"mangarr/internal/domain"
+)
+
+// comickTestTransport answers requests to the api with the fixtures in testdata/comick
+type comickTestTransport struct {
+	mu    sync.Mutex
+	paths []string
+}
+
+func (c *comickTestTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.mu.Lock()
+	c.paths = append(c.paths, req.URL.Path)
+	c.mu.Unlock()
+
+	var name string
+	switch path := req.URL.Path; {
+	case strings.HasSuffix(path, "/chapters"):
+		name = "chapters_page1.json"
+		if req.URL.Query().Get("page") == "2" {
+			name = "chapters_page2.json"
+		}
+	case strings.HasPrefix(path, "/comic/"):
+		name = "comic.json"
+	case strings.HasPrefix(path, "/chapter/"):
+		name = "chapter.json"
+	}
+
+	rec := httptest.NewRecorder()
+	if len(name) == 0 {
+		http.NotFound(rec, req)
+	} else {
+		http.ServeFile(rec, req, filepath.Join("testdata", "comick", name))
+	}
+
+	return rec.Result(), nil
+}
+
+func newTestComick(group string) (*comick, *comickTestTransport) {
+	transport := &comickTestTransport{}
+
+	return &comick{
+		Slug:     "omniscient-readers-viewpoint",
+		Group:    group,
+		Language: "en",
+		Client:   &http.Client{Transport: transport},
+	}, transport
+}
+
+func TestComickGetManga(t *testing.T) {
+	c, transport := newTestComick("")
+
+	manga, err := c.GetManga(context.Background())
+	if err != nil {
+		t.Fatalf("GetManga: %v", err)
+	}
+
+	if manga.Title != "Omniscient Reader's Viewpoint" {
+		t.Errorf("Title = %q", manga.Title)
+	}
+	if manga.URL != comickSiteURL+"/comic/omniscient-readers-viewpoint" {
+		t.Errorf("URL = %q", manga.URL)
+	}
+	if c.HID != "tK3xNHrL" {
+		t.Errorf("HID = %q, want %q", c.HID, "tK3xNHrL")
+	}
+
+	m := manga.Metadata
+	if m.Synopsis != "Only I know the end of this world." {
+		t.Errorf("Synopsis = %q", m.Synopsis)
+	}
+	if m.Status != domain.StatusOngoing || m.ReadingDirection != domain.DirectionVertical {
+		t.Errorf("Status = %q, ReadingDirection = %q", m.Status, m.ReadingDirection)
+	}
+	if m.CoverURL != comickImagesURL+"/b9Wz3.jpg" {
+		t.Errorf("CoverURL = %q", m.CoverURL)
+	}
+	if want := []string{"Jeonjijeok Dokja Sijeom", "ORV"}; !slices.Equal(m.AltTitles, want) {
+		t.Errorf("AltTitles = %v, want %v", m.AltTitles, want)
+	}
+	if !slices.Equal(m.Authors, []string{"Sing Shong"}) || !slices.Equal(m.Artists, []string{"Sleepy-C"}) {
+		t.Errorf("Authors = %v, Artists = %v", m.Authors, m.Artists)
+	}
+
+	if err := c.GetChapters(context.Background(), manga); err != nil {
+		t.Fatalf("GetChapters: %v", err)
+	}
+
+	// the chapters are listed by the hid of the comic
+	if !slices.Contains(transport.paths, "/comic/tK3xNHrL/chapters") {
+		t.Errorf("requested paths = %v", transport.paths)
+	}
+
+	if releases := manga.Chapters["1"]; len(releases) != 2 {
+		t.Fatalf("releases of chapter 1 = %d, want 2", len(releases))
+	}
+
+	first := manga.Chapters["1"][0]
+	if first.ID != "aB1" || first.Volume != "1" || first.Title != "Prologue" || first.Group != "Asura Scans" {
+		t.Errorf("chapter 1 = %+v", first)
+	}
+	if first.URL != comickSiteURL+"/comic/omniscient-readers-viewpoint/aB1" {
+		t.Errorf("chapter 1: URL = %q", first.URL)
+	}
+	if !first.PublishedAt.Equal(time.Date(2020, time.May, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("chapter 1: PublishedAt = %s", first.PublishedAt)
+	}
+	if !first.IsManhwa {
+		t.Error("chapter 1 of a korean comic is not a manhwa")
+	}
+
+	// chapters without a number are oneshots, they are listed on the second page
+	if releases := manga.Chapters["oneshot"]; len(releases) != 1 || releases[0].Title != "Side Story" {
+		t.Errorf("releases of oneshot = %+v", releases)
+	}
+}
+
+func TestComickGetChaptersOfGroup(t *testing.T) {
+	c, _ := newTestComick("Asura Scans")
+
+	manga, err := c.GetManga(context.Background())
+	if err != nil {
+		t.Fatalf("GetManga: %v", err)
+	}
+
+	if err := c.GetChapters(context.Background(), manga); err != nil {
+		t.Fatalf("GetChapters: %v", err)
+	}
+
+	var ids []string
+	for _, releases := range manga.Chapters {
+		for _, release := range releases {
+			ids = append(ids, release.ID)
+		}
+	}
+	slices.Sort(ids)
+
+	// group names are compared ignoring case and surrounding spaces
+	if want := []string{"aB1", "aB3"}; !slices.Equal(ids, want) {
+		t.Errorf("chapter ids = %v, want %v", ids, want)
+	}
+}
+
+func TestComickGetImageURLs(t *testing.T) {
+	c, transport := newTestComick("")
+
+	chapter := domain.Chapter{ID: "aB1"}
+	if err := c.GetImageURLs(context.Background(), &chapter); err != nil {
+		t.Fatalf("GetImageURLs: %v", err)
+	}
+
+	if !slices.Contains(transport.paths, "/chapter/aB1/") {
+		t.Errorf("requested paths = %v", transport.paths)
+	}
+
+	want := []string{comickImagesURL + "/0001-page.jpg", comickImagesURL + "/0002-page.jpg"}
+	if got := imageInfoURLs(chapter.ImageInfo); !slices.Equal(got, want) {
+		t.Errorf("images = %v, want %v", got, want)
+	}
+
+	if chapter.ImageInfo[0].Width != 800 || chapter.ImageInfo[0].Height != 12000 {
+		t.Errorf("size of page 1 = %vx%v", chapter.ImageInfo[0].Width, chapter.ImageInfo[0].Height)
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
-	"strconv"
 	"strings"
 	"time"
 
@@ -18,24 +17,72 @@ import (
 	"github.com/avast/retry-go"
 )
 
+const cubariURL = "https://cubari.moe"
+
 type cubari struct {
 	MangaURL string
 	GroupID  string
 	Client   *http.Client
+
+	// baseURL is the cubari instance proxy paths in the groups are relative to
+	baseURL string
+	// sourceURL is the url the manga was given as, MangaURL is changed to the api url for reader urls
+	sourceURL string
 }
 
 type cubariResponse struct {
+	Artist      string `json:"artist"`
+	Author      string `json:"author"`
 	Cover       string `json:"cover"`
 	Description string `json:"description"`
 	Title       string `json:"title"`
-	Chapters    map[string]struct {
-		Groups      map[string][]string `json:"groups"`
-		LastUpdated int64               `json:"last_updated"`
-		Title       string              `json:"title"`
-		Volume      string              `json:"volume"`
+	// Groups maps the keys of the groups used by the chapters to their names
+	Groups   map[string]string `json:"groups"`
+	Chapters map[string]struct {
+		// Groups holds the images of the chapter by group, either as list or as proxy path returning the list
+		Groups      map[string]json.RawMessage `json:"groups"`
+		LastUpdated unixTime                   `json:"last_updated"`
+		ReleaseDate map[string]unixTime        `json:"release_date"`
+		Title       string                     `json:"title"`
+		Volume      string                     `json:"volume"`
 	} `json:"chapters"`
 }
 
+// cubariImage is an image of a chapter, which is listed either as url or as object holding the url
+type cubariImage struct {
+	Src string `json:"src"`
+}
+
+func (i *cubariImage) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &i.Src); err == nil {
+		return nil
+	}
+
+	var image struct {
+		Src string `json:"src"`
+	}
+	if err := json.Unmarshal(data, &image); err != nil {
+		return err
+	}
+
+	i.Src = image.Src
+	return nil
+}
+
+func init() {
+	Register(Definition{
+		Key:  "cubari",
+		Name: "Cubari",
+		Fields: []Field{
+			{Name: InputManga, Required: true, Help: "URL of the gist for the manga or of the manga on the Cubari reader", Example: "https://cubari.moe/read/gist/OPM/"},
+			{Name: InputGroup, Required: true, Help: "Name or key of the group in the groups of the manga", Example: "/r/OnePunchMan"},
+		},
+		New: func(in Input) domain.Source {
+			return NewCubari(in.Manga, in.Group)
+		},
+	})
+}
+
 func NewCubari(mangaURL, groupID string) domain.Source {
 	client := http.Client{
 		Timeout:   60 * time.Second,
@@ -54,7 +101,8 @@ func (c *cubari) String() string {
 }
 
 func (c *cubari) ValidateInput() error {
-	if _, err := url.Parse(c.MangaURL); err != nil {
+	u, err := url.Parse(c.MangaURL)
+	if err != nil {
 		return err
 	}
 
@@ -62,38 +110,36 @@ func (c *cubari) ValidateInput() error {
 		return fmt.Errorf("cubari group id is required")
 	}
 
-	return nil
-}
+	c.baseURL = cubariURL
+	c.sourceURL = c.MangaURL
 
-func (c *cubari) GetManga(ctx context.Context) (domain.Manga, error) {
-	var cubariResp cubariResponse
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.MangaURL, nil)
-	if err != nil {
-		return domain.Manga{}, fmt.Errorf("failed to create request: %w", err)
-	}
+	// reader urls like https://cubari.moe/read/gist/<slug>/ are served by the api of the reader
+	if strings.HasPrefix(u.Hostname(), "cubari.") {
+		segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+		if len(segments) < 3 || segments[0] != "read" {
+			return fmt.Errorf("the url for cubari must point to a manga, e.g. %s/read/gist/<slug>/", cubariURL)
+		}
 
-	req.Header.Set("User-Agent", "mangarr")
+		c.baseURL = u.Scheme + "://" + u.Host
 
-	retryErr := retry.Do(func() error {
-		resp, err := sharedhttp.ExecRequest(*c.Client, req)
-		if err != nil {
-			return err
+		if segments[1] != "api" {
+			c.MangaURL, err = url.JoinPath(c.baseURL, "read", "api", segments[1], "series", segments[2])
+			if err != nil {
+				return err
+			}
+			c.MangaURL += "/"
 		}
+	}
 
-		buf := bufio.NewReader(resp.Body)
+	return nil
+}
 
-		err = json.NewDecoder(buf).Decode(&cubariResp)
-		if err != nil {
-			return retry.Unrecoverable(err)
-		}
+func (c *cubari) GetManga(ctx context.Context) (domain.Manga, error) {
+	var cubariResp cubariResponse
 
-		return nil
-	},
-		retry.Delay(time.Second*3),
-		retry.Attempts(3),
-		retry.MaxJitter(time.Second*1),
-	)
+	if err := c.get(ctx, c.MangaURL, &cubariResp); err != nil {
+		return domain.Manga{}, err
+	}
 
 	title := cubariResp.Title
 	if len(title) == 0 {
@@ -101,51 +147,155 @@ func (c *cubari) GetManga(ctx context.Context) (domain.Manga, error) {
 	}
 
 	manga := domain.Manga{
-		Title:    sanitize.Filename(title),
-		Chapters: make(map[float32]domain.Chapter),
+		Title: sanitize.Filename(title),
+		Metadata: domain.Metadata{
+			Synopsis:  cubariResp.Description,
+			CoverURL:  cubariResp.Cover,
+			SourceURL: c.sourceURL,
+		},
+		Chapters: make(map[string][]domain.Chapter),
+	}
+
+	if len(cubariResp.Author) != 0 {
+		manga.Metadata.Authors = []string{cubariResp.Author}
 	}
+	if len(cubariResp.Artist) != 0 {
+		manga.Metadata.Artists = []string{cubariResp.Artist}
+	}
+
+	groupKey, groupName := c.findGroup(cubariResp.Groups)
 
 	for num, chapter := range cubariResp.Chapters {
-		chapterNum64, err := strconv.ParseFloat(num, 32)
-		if err != nil {
-			return domain.Manga{}, err
+		raw, ok := chapter.Groups[groupKey]
+		if !ok {
+			continue
 		}
 
-		chapterNum := float32(chapterNum64)
+		chapterNum := domain.ParseChapterNumber(num)
 		chapterTitle := c.getChapterName(chapter.Title)
 
-		if imageURLs, ok := chapter.Groups[c.GroupID]; ok {
-			var imageInfos []domain.ImageInfo
+		publishedAt := chapter.ReleaseDate[groupKey].Time
+		if publishedAt.IsZero() {
+			publishedAt = chapter.LastUpdated.Time
+		}
 
-			for _, imageURL := range imageURLs {
-				imageInfos = append(imageInfos, domain.ImageInfo{ImageURL: imageURL})
-			}
+		release := domain.Chapter{
+			Number:      chapterNum,
+			Volume:      chapter.Volume,
+			Title:       sanitize.Filename(chapterTitle),
+			PublishedAt: publishedAt,
+			Group:       groupName,
+		}
 
-			manga.Chapters[chapterNum] = domain.Chapter{
-				Number:    chapterNum,
-				Title:     sanitize.Filename(chapterTitle),
-				ImageInfo: imageInfos,
-			}
+		// proxied chapters only list their images once the proxy path is requested
+		var proxyPath string
+		if err := json.Unmarshal(raw, &proxyPath); err == nil {
+			release.URL = c.baseURL + "/" + strings.TrimPrefix(proxyPath, "/")
+			manga.AddChapter(release)
+			continue
 		}
+
+		var images []cubariImage
+		if err := json.Unmarshal(raw, &images); err != nil {
+			return domain.Manga{}, fmt.Errorf("failed to parse images of chapter %s: %w", num, err)
+		}
+
+		release.ImageInfo = cubariImageInfos(images)
+		release.Pages = len(release.ImageInfo)
+		manga.AddChapter(release)
 	}
 
 	if len(manga.Chapters) == 0 {
 		return domain.Manga{}, fmt.Errorf("failed to get chapters for manga: %s", manga.Title)
 	}
 
-	return manga, retryErr
+	return manga, nil
 }
 
 func (c *cubari) GetChapters(_ context.Context, _ domain.Manga) error {
 	return nil
 }
 
-func (c *cubari) GetImageURLs(_ context.Context, _ *domain.Chapter) error {
+func (c *cubari) GetImageURLs(ctx context.Context, chapter *domain.Chapter) error {
+	// the images of chapters that aren't proxied are already known
+	if len(chapter.URL) == 0 {
+		return nil
+	}
+
+	var images []cubariImage
+	if err := c.get(ctx, chapter.URL, &images); err != nil {
+		return err
+	}
+
+	imageInfos := cubariImageInfos(images)
+	if len(imageInfos) == 0 {
+		return fmt.Errorf("failed to get image urls for chapter number: %s", chapter.Number)
+	}
+
+	chapter.ImageInfo = imageInfos
 	return nil
 }
 
+// get requests the url and decodes the json response into v
+func (c *cubari) get(ctx context.Context, u string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "mangarr")
+
+	return retry.Do(func() error {
+		resp, err := sharedhttp.ExecRequest(*c.Client, req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		buf := bufio.NewReader(resp.Body)
+
+		err = json.NewDecoder(buf).Decode(v)
+		if err != nil {
+			return retry.Unrecoverable(err)
+		}
+
+		return nil
+	},
+		retry.Delay(time.Second*3),
+		retry.Attempts(3),
+		retry.MaxJitter(time.Second*1),
+	)
+}
+
+// findGroup returns the key and name of the configured group, which is either the name of a group in the groups of
+// the manga or the key the chapters list the group by
+func (c *cubari) findGroup(groups map[string]string) (string, string) {
+	for key, name := range groups {
+		if strings.EqualFold(strings.TrimSpace(name), strings.TrimSpace(c.GroupID)) {
+			return key, name
+		}
+	}
+
+	if name, ok := groups[c.GroupID]; ok && len(name) != 0 {
+		return c.GroupID, name
+	}
+
+	return c.GroupID, c.GroupID
+}
+
 func (c *cubari) getChapterName(chapterString string) string {
 	colonIndex := strings.Index(chapterString, ":")
 
 	return strings.TrimSpace(chapterString[colonIndex+1:])
 }
+
+func cubariImageInfos(images []cubariImage) []domain.ImageInfo {
+	var imageInfos []domain.ImageInfo
+	for _, image := range images {
+		if len(image.Src) != 0 {
+			imageInfos = append(imageInfos, domain.ImageInfo{ImageURL: image.Src})
+		}
+	}
+
+	return imageInfos
+}
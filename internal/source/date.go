@@ -0,0 +1,68 @@
+package source
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ordinalSuffixPattern = regexp.MustCompile(`(\d+)(st|nd|rd|th)\b`)
+	// relativeDatePattern matches countdowns like "in 3 days" or "in 12h"
+	relativeDatePattern = regexp.MustCompile(`(?i)\bin\s+(\d+)\s*(m|mins?|minutes?|h|hrs?|hours?|d|days?|w|weeks?)\b`)
+)
+
+// parseReleaseDate parses a release date shown on a website, it returns the zero time if no layout matches
+func parseReleaseDate(value string, layouts ...string) time.Time {
+	value = ordinalSuffixPattern.ReplaceAllString(strings.TrimSpace(value), "$1")
+
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// parseRelativeDate parses a countdown shown on a website relative to now, it returns the zero time if there is none
+func parseRelativeDate(value string, now time.Time) time.Time {
+	matches := relativeDatePattern.FindStringSubmatch(value)
+	if matches == nil {
+		return time.Time{}
+	}
+
+	amount, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return time.Time{}
+	}
+
+	var unit time.Duration
+	switch strings.ToLower(matches[2])[0] {
+	case 'm':
+		unit = time.Minute
+	case 'h':
+		unit = time.Hour
+	case 'd':
+		unit = 24 * time.Hour
+	case 'w':
+		unit = 7 * 24 * time.Hour
+	}
+
+	return now.Add(time.Duration(amount) * unit)
+}
+
+// unixTime is a unix timestamp, which may be given as number or string, timestamps that can't be parsed are left zero
+type unixTime struct {
+	time.Time
+}
+
+func (t *unixTime) UnmarshalJSON(data []byte) error {
+	seconds, err := strconv.ParseFloat(strings.Trim(string(data), `"`), 64)
+	if err == nil && seconds > 0 {
+		t.Time = time.Unix(int64(seconds), 0)
+	}
+
+	return nil
+}
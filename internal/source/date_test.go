@@ -0,0 +1,62 @@
+package source
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRelativeDate(t *testing.T) {
+	now := time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Duration
+		zero  bool
+	}{
+		{value: "Public in 3 days", want: 3 * 24 * time.Hour},
+		{value: "Free in 1 day", want: 24 * time.Hour},
+		{value: "available in 12h", want: 12 * time.Hour},
+		{value: "Unlocks In 5 Hours", want: 5 * time.Hour},
+		{value: "in 30 mins", want: 30 * time.Minute},
+		{value: "in 2 weeks", want: 14 * 24 * time.Hour},
+		{value: "Early Access", zero: true},
+		{value: "March 1st 2025", zero: true},
+		{value: "in 3 decades", zero: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got := parseRelativeDate(tt.value, now)
+
+			if tt.zero {
+				if !got.IsZero() {
+					t.Errorf("parseRelativeDate = %s, want zero time", got)
+				}
+				return
+			}
+
+			if want := now.Add(tt.want); !got.Equal(want) {
+				t.Errorf("parseRelativeDate = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestParseReleaseDate(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{value: "March 1st 2025", want: time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)},
+		{value: " February 22nd 2025 ", want: time.Date(2025, time.February, 22, 0, 0, 0, 0, time.UTC)},
+		{value: "Public in 3 days"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := parseReleaseDate(tt.value, "January 2 2006"); !got.Equal(tt.want) {
+				t.Errorf("parseReleaseDate = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
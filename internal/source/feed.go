@@ -0,0 +1,398 @@
+package source
+
+import (
+	"bufio"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"mangarr/internal/domain"
+	"mangarr/internal/sanitize"
+	"mangarr/internal/sharedhttp"
+
+	"github.com/avast/retry-go"
+	"github.com/gocolly/colly"
+)
+
+// feed options
+const (
+	feedOptionTitle         = "title"
+	feedOptionTitlePattern  = "titlePattern"
+	feedOptionImageSelector = "imageSelector"
+	feedOptionImageAttr     = "imageAttr"
+	feedOptionScraper       = "scraper"
+)
+
+var defaultFeedTitlePattern = regexp.MustCompile(`(?i)(?:chapter|ch\.?|episode|ep\.?)\s*(\d+(?:\.\d+)?[a-z]?)\b`)
+
+var feedArchiveTypes = []string{"application/zip", "application/x-zip-compressed", "application/x-cbz", "application/vnd.comicbook+zip"}
+
+type feedDocument struct {
+	// rss
+	Channel struct {
+		Title string        `xml:"title"`
+		Items []feedRSSItem `xml:"item"`
+	} `xml:"channel"`
+
+	// atom
+	Title   string          `xml:"title"`
+	Entries []feedAtomEntry `xml:"entry"`
+}
+
+type feedRSSItem struct {
+	Title     string `xml:"title"`
+	Link      string `xml:"link"`
+	GUID      string `xml:"guid"`
+	PubDate   string `xml:"pubDate"`
+	Enclosure struct {
+		URL  string `xml:"url,attr"`
+		Type string `xml:"type,attr"`
+	} `xml:"enclosure"`
+}
+
+type feedAtomEntry struct {
+	ID        string `xml:"id"`
+	Title     string `xml:"title"`
+	Updated   string `xml:"updated"`
+	Published string `xml:"published"`
+	Links     []struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+		Type string `xml:"type,attr"`
+	} `xml:"link"`
+}
+
+// feedEntry is an entry of either feed format
+type feedEntry struct {
+	ID         string
+	Title      string
+	URL        string
+	ArchiveURL string
+	Published  time.Time
+}
+
+type feed struct {
+	Client        *http.Client
+	FeedURL       string
+	Title         string
+	Group         string
+	Language      string
+	Pattern       string
+	ImageSelector string
+	ImageAttr     string
+	Scraper       string
+
+	titlePattern *regexp.Regexp
+}
+
+func init() {
+	Register(Definition{
+		Key:  "feed",
+		Name: "RSS/Atom feed",
+		Fields: []Field{
+			{Name: InputManga, Required: true, Help: "URL of the RSS or Atom feed", Example: "https://mysite.com/feed/"},
+			{Name: InputGroup, Help: "Name used as group of the chapters, defaults to the title of the feed"},
+			{Name: InputLanguage, Help: "Language of the chapters"},
+		},
+		Options: []Option{
+			{Name: feedOptionTitle, Help: "Title of the manga, defaults to the title of the feed"},
+			{Name: feedOptionTitlePattern, Help: "Regex matching the titles of the entries of the manga, its first capture group is the chapter number", Example: `Solo Leveling Chapter (\d+)`},
+			{Name: feedOptionImageSelector, Help: "Selector of the images on the page an entry links to", Example: "#readerarea img"},
+			{Name: feedOptionImageAttr, Help: "Attribute of the images holding their url, defaults to the first of data-src, data-lazy-src and src"},
+			{Name: feedOptionScraper, Help: "Custom or theme source whose images are used for the pages the entries link to", Example: "mysite"},
+		},
+		New: func(in Input) domain.Source {
+			return NewFeed(in)
+		},
+	})
+}
+
+func NewFeed(in Input) domain.Source {
+	client := &http.Client{
+		Timeout:   60 * time.Second,
+		Transport: sharedhttp.Transport,
+	}
+
+	return &feed{
+		Client:        client,
+		FeedURL:       in.Manga,
+		Title:         in.Option(feedOptionTitle),
+		Group:         in.Group,
+		Language:      in.Language,
+		Pattern:       in.Option(feedOptionTitlePattern),
+		ImageSelector: in.Option(feedOptionImageSelector),
+		ImageAttr:     in.Option(feedOptionImageAttr),
+		Scraper:       in.Option(feedOptionScraper),
+	}
+}
+
+func (f *feed) String() string {
+	return "feed"
+}
+
+func (f *feed) ValidateInput() error {
+	u, err := url.Parse(f.FeedURL)
+	if err != nil {
+		return err
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("the feed url must be a http or https url")
+	}
+
+	f.titlePattern = defaultFeedTitlePattern
+	if len(f.Pattern) != 0 {
+		pattern, err := regexp.Compile(f.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid title pattern: %w", err)
+		}
+		f.titlePattern = pattern
+	}
+
+	if len(f.ImageSelector) != 0 && len(f.Scraper) != 0 {
+		return fmt.Errorf("only one of the image selector and scraper options can be set")
+	}
+
+	return nil
+}
+
+func (f *feed) GetManga(ctx context.Context) (domain.Manga, error) {
+	title, entries, err := f.getEntries(ctx)
+	if err != nil {
+		return domain.Manga{}, err
+	}
+
+	if len(f.Title) != 0 {
+		title = f.Title
+	}
+
+	if len(title) == 0 {
+		return domain.Manga{}, fmt.Errorf("failed to get manga for provided url: %s", f.FeedURL)
+	}
+
+	group := f.Group
+	if len(group) == 0 {
+		group = title
+	}
+
+	manga := domain.Manga{
+		URL:   f.FeedURL,
+		Title: sanitize.Filename(title),
+		Metadata: domain.Metadata{
+			SourceURL: f.FeedURL,
+		},
+		Chapters: make(map[string][]domain.Chapter),
+	}
+
+	for _, entry := range entries {
+		label := matchFirstGroup(f.titlePattern, entry.Title)
+		if len(label) == 0 {
+			continue
+		}
+
+		if len(entry.URL) == 0 && len(entry.ArchiveURL) == 0 {
+			continue
+		}
+
+		manga.AddChapter(domain.Chapter{
+			ID:          entry.ID,
+			URL:         entry.URL,
+			ArchiveURL:  entry.ArchiveURL,
+			Number:      domain.ParseChapterNumber(label),
+			PublishedAt: entry.Published,
+			Group:       group,
+			Language:    f.Language,
+		})
+	}
+
+	if len(manga.Chapters) == 0 {
+		return domain.Manga{}, fmt.Errorf("failed to get chapters for manga: %s", manga.Title)
+	}
+
+	return manga, nil
+}
+
+func (f *feed) GetChapters(_ context.Context, _ domain.Manga) error {
+	return nil
+}
+
+func (f *feed) GetImageURLs(ctx context.Context, chapter *domain.Chapter) error {
+	// the archive is downloaded as is
+	if len(chapter.ArchiveURL) != 0 {
+		return nil
+	}
+
+	if len(f.Scraper) != 0 {
+		def, ok := Lookup(f.Scraper)
+		if !ok {
+			return unknownSourceError(f.Scraper)
+		}
+
+		// sources set up the state they need to fetch images while validating their input
+		s := def.New(Input{Manga: chapter.URL})
+		if err := s.ValidateInput(); err != nil {
+			return &InputError{Source: f.Scraper, Err: err}
+		}
+
+		return s.GetImageURLs(ctx, chapter)
+	}
+
+	if len(f.ImageSelector) == 0 {
+		return fmt.Errorf("entry of chapter %s links to a page, set the %s or %s option to get its images", chapter.Number, feedOptionImageSelector, feedOptionScraper)
+	}
+
+	c := newCollector(ctx)
+
+	var imageInfos []domain.ImageInfo
+
+	c.OnHTML(f.ImageSelector, func(e *colly.HTMLElement) {
+		imgURL := imageURL(e, f.ImageAttr)
+		if len(imgURL) == 0 {
+			return
+		}
+
+		imageInfos = append(imageInfos, domain.ImageInfo{ImageURL: e.Request.AbsoluteURL(imgURL)})
+	})
+
+	err := visit(ctx, c, chapter.URL)
+	if err != nil {
+		return err
+	}
+
+	if len(imageInfos) == 0 {
+		return fmt.Errorf("failed to get image urls for chapter number: %s", chapter.Number)
+	}
+
+	chapter.ImageInfo = imageInfos
+	return nil
+}
+
+// getEntries fetches the feed and returns its title and entries
+func (f *feed) getEntries(ctx context.Context) (string, []feedEntry, error) {
+	var doc feedDocument
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.FeedURL, nil)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "mangarr")
+
+	retryErr := retry.Do(func() error {
+		resp, err := sharedhttp.ExecRequest(*f.Client, req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		err = xml.NewDecoder(bufio.NewReader(resp.Body)).Decode(&doc)
+		if err != nil {
+			return retry.Unrecoverable(fmt.Errorf("failed to parse feed: %w", err))
+		}
+
+		return nil
+	},
+		retry.Delay(time.Second*3),
+		retry.Attempts(3),
+		retry.MaxJitter(time.Second*1),
+	)
+	if retryErr != nil {
+		return "", nil, retryErr
+	}
+
+	var entries []feedEntry
+
+	for _, item := range doc.Channel.Items {
+		entry := feedEntry{
+			ID:        strings.TrimSpace(item.GUID),
+			Title:     strings.TrimSpace(item.Title),
+			URL:       strings.TrimSpace(item.Link),
+			Published: parseReleaseDate(item.PubDate, time.RFC1123Z, time.RFC1123, time.RFC3339),
+		}
+
+		if enclosure := strings.TrimSpace(item.Enclosure.URL); len(enclosure) != 0 && isArchive(enclosure, item.Enclosure.Type) {
+			entry.ArchiveURL = enclosure
+		} else if isArchive(entry.URL, "") {
+			entry.ArchiveURL = entry.URL
+		}
+
+		entries = append(entries, entry)
+	}
+
+	for _, atomEntry := range doc.Entries {
+		entry := feedEntry{
+			ID:        strings.TrimSpace(atomEntry.ID),
+			Title:     strings.TrimSpace(atomEntry.Title),
+			Published: parseReleaseDate(atomEntry.Published, time.RFC3339),
+		}
+
+		if entry.Published.IsZero() {
+			entry.Published = parseReleaseDate(atomEntry.Updated, time.RFC3339)
+		}
+
+		for _, link := range atomEntry.Links {
+			href := strings.TrimSpace(link.Href)
+
+			switch {
+			case isArchive(href, link.Type):
+				entry.ArchiveURL = href
+			case (link.Rel == "" || link.Rel == "alternate") && len(entry.URL) == 0:
+				entry.URL = href
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	title := strings.TrimSpace(doc.Channel.Title)
+	if len(title) == 0 {
+		title = strings.TrimSpace(doc.Title)
+	}
+
+	return title, entries, nil
+}
+
+// isArchive reports whether the link points to a CBZ or ZIP archive judging by its media type or extension
+func isArchive(link, mediaType string) bool {
+	if len(link) == 0 {
+		return false
+	}
+
+	for _, archiveType := range feedArchiveTypes {
+		if strings.EqualFold(mediaType, archiveType) {
+			return true
+		}
+	}
+
+	u, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+
+	switch strings.ToLower(path.Ext(u.Path)) {
+	case ".cbz", ".zip":
+		return true
+	default:
+		return false
+	}
+}
+
+// matchFirstGroup returns the first capture group of the match of pattern in s, or the whole match if the pattern
+// has no groups
+func matchFirstGroup(pattern *regexp.Regexp, s string) string {
+	matches := pattern.FindStringSubmatch(s)
+	switch {
+	case matches == nil:
+		return ""
+	case len(matches) > 1:
+		return strings.TrimSpace(matches[1])
+	default:
+		return strings.TrimSpace(matches[0])
+	}
+}
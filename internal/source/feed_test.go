@@ -0,0 +1,72 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"mangarr/internal/domain"
+)
+
+// validatedScraper only returns images after ValidateInput set it up, like sources normalizing their input
+type validatedScraper struct {
+	url       string
+	validated bool
+}
+
+func (s *validatedScraper) String() string { return "validated scraper" }
+
+func (s *validatedScraper) ValidateInput() error {
+	if s.url == "invalid" {
+		return errors.New("invalid url")
+	}
+	s.validated = true
+	return nil
+}
+
+func (s *validatedScraper) GetManga(context.Context) (domain.Manga, error) {
+	return domain.Manga{}, nil
+}
+
+func (s *validatedScraper) GetChapters(context.Context, domain.Manga) error { return nil }
+
+func (s *validatedScraper) GetImageURLs(_ context.Context, chapter *domain.Chapter) error {
+	if !s.validated {
+		return fmt.Errorf("input of %s was not validated", s.url)
+	}
+	chapter.ImageInfo = []domain.ImageInfo{{ImageURL: s.url + "/1.jpg"}}
+	return nil
+}
+
+func TestFeedScraperValidatesInput(t *testing.T) {
+	Register(Definition{
+		Key: "feedtest-scraper",
+		New: func(in Input) domain.Source {
+			return &validatedScraper{url: in.Manga}
+		},
+	})
+	t.Cleanup(func() {
+		registryMu.Lock()
+		defer registryMu.Unlock()
+		delete(registry, "feedtest-scraper")
+	})
+
+	f := &feed{Scraper: "feedtest-scraper"}
+
+	chapter := domain.Chapter{URL: "https://example.com/chapter-1"}
+	if err := f.GetImageURLs(context.Background(), &chapter); err != nil {
+		t.Fatalf("GetImageURLs: %v", err)
+	}
+	if len(chapter.ImageInfo) != 1 || chapter.ImageInfo[0].ImageURL != "https://example.com/chapter-1/1.jpg" {
+		t.Errorf("ImageInfo = %+v", chapter.ImageInfo)
+	}
+
+	invalid := domain.Chapter{URL: "invalid"}
+	err := f.GetImageURLs(context.Background(), &invalid)
+
+	var inputErr *InputError
+	if !errors.As(err, &inputErr) {
+		t.Errorf("GetImageURLs of invalid url = %v, want InputError", err)
+	}
+}
@@ -2,35 +2,162 @@ package source
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
 	"net/url"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"mangarr/internal/domain"
 	"mangarr/internal/sanitize"
+	"mangarr/internal/sharedhttp"
 
-	"github.com/gocolly/colly"
-	"github.com/gocolly/colly/extensions"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/avast/retry-go"
 )
 
+const (
+	flamecomicsURL    = "https://flamecomics.xyz"
+	flamecomicsCDNURL = "https://cdn.flamecomics.xyz"
+)
+
+var (
+	flamecomicsSeriesID = regexp.MustCompile(`^\d+$`)
+	// the old site prefixed the slugs of series with a timestamp and named chapters <slug>-chapter-<number>
+	flamecomicsSlugPrefix    = regexp.MustCompile(`^\d+-`)
+	flamecomicsChapterSuffix = regexp.MustCompile(`-chapter-[\d-]+$`)
+	flamecomicsNonAlnum      = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// the image server rejects requests that don't come from the site
+var flamecomicsHeaders = map[string]string{
+	"Referer": flamecomicsURL + "/",
+}
+
 type flamecomics struct {
-	MangaURL  string
-	Collector colly.Collector
+	MangaURL string
+	Client   *http.Client
+
+	seriesID string
+	// slug is the series of a url of the old site, it is looked up by title as the new site only knows series ids
+	slug string
 }
 
-func NewFlamecomics(mangaURL string) domain.Source {
-	collector := colly.NewCollector(
-		colly.AllowURLRevisit(),
-	)
-	extensions.RandomUserAgent(collector)
+// flamecomicsPage is the data of a series or chapter page embedded in __NEXT_DATA__
+type flamecomicsPage struct {
+	Props struct {
+		PageProps struct {
+			Series   flamecomicsSeries    `json:"series"`
+			Chapters []flamecomicsChapter `json:"chapters"`
+			Chapter  flamecomicsChapter   `json:"chapter"`
+		} `json:"pageProps"`
+	} `json:"props"`
+}
+
+// flamecomicsBrowse is the data of the browse page listing all series embedded in __NEXT_DATA__
+type flamecomicsBrowse struct {
+	Props struct {
+		PageProps struct {
+			Series []flamecomicsSeries `json:"series"`
+		} `json:"pageProps"`
+	} `json:"props"`
+}
+
+type flamecomicsSeries struct {
+	SeriesID    flamecomicsValue `json:"series_id"`
+	Title       string           `json:"title"`
+	AltTitles   flamecomicsList  `json:"altTitles"`
+	Description string           `json:"description"`
+	Cover       string           `json:"cover"`
+	Status      string           `json:"status"`
+	Tags        flamecomicsList  `json:"tags"`
+	Author      flamecomicsList  `json:"author"`
+	Artist      flamecomicsList  `json:"artist"`
+}
+
+type flamecomicsChapter struct {
+	ChapterID   flamecomicsValue `json:"chapter_id"`
+	SeriesID    flamecomicsValue `json:"series_id"`
+	Chapter     flamecomicsValue `json:"chapter"`
+	Title       string           `json:"title"`
+	Token       string           `json:"token"`
+	ReleaseDate unixTime         `json:"release_date"`
+	// Images holds the pages of the chapter, either as list or as object indexed by page
+	Images json.RawMessage `json:"images"`
+}
+
+type flamecomicsImage struct {
+	Name   string  `json:"name"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// flamecomicsValue is a value that may be given as number or string
+type flamecomicsValue string
+
+func (v *flamecomicsValue) UnmarshalJSON(data []byte) error {
+	value := strings.Trim(string(data), `"`)
+	if value == "null" {
+		value = ""
+	}
+
+	*v = flamecomicsValue(value)
+	return nil
+}
+
+// flamecomicsList is a list of names, which may be given as list, as json encoded list or as comma separated string
+type flamecomicsList []string
+
+func (l *flamecomicsList) UnmarshalJSON(data []byte) error {
+	var values []string
+	if err := json.Unmarshal(data, &values); err == nil {
+		*l = values
+		return nil
+	}
+
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil
+	}
+
+	if err := json.Unmarshal([]byte(value), &values); err == nil {
+		*l = values
+		return nil
+	}
+
+	*l = strings.Split(value, ",")
+	return nil
+}
+
+func init() {
+	Register(Definition{
+		Key:  "flamecomics",
+		Name: "Flame Comics",
+		Fields: []Field{
+			{Name: InputManga, Required: true, Help: "URL or id of the series on Flame Comics, URLs of the old site are looked up by title", Example: "https://flamecomics.xyz/series/2"},
+		},
+		Languages: []string{"en"},
+		Manhwa:    true,
+		New: func(in Input) domain.Source {
+			return NewFlamecomics(in.Manga)
+		},
+	})
+}
 
-	collector.SetRequestTimeout(120 * time.Second)
+func NewFlamecomics(mangaURL string) domain.Source {
+	client := http.Client{
+		Timeout:   60 * time.Second,
+		Transport: sharedhttp.Transport,
+	}
 
 	return &flamecomics{
-		MangaURL:  mangaURL,
-		Collector: *collector,
+		MangaURL: mangaURL,
+		Client:   &client,
 	}
 }
 
@@ -39,52 +166,106 @@ func (f *flamecomics) String() string {
 }
 
 func (f *flamecomics) ValidateInput() error {
-	if !strings.HasPrefix(f.MangaURL, "https://flamecomics.xyz") {
-		return fmt.Errorf("the url for flamecomics must start with https://flamecomics.xyz")
+	if flamecomicsSeriesID.MatchString(f.MangaURL) {
+		f.seriesID = f.MangaURL
+		return nil
 	}
 
-	if _, err := url.Parse(f.MangaURL); err != nil {
+	u, err := url.Parse(f.MangaURL)
+	if err != nil {
 		return err
 	}
 
-	return nil
-}
+	if !strings.Contains(u.Hostname(), "flamecomics") && !strings.Contains(u.Hostname(), "flamescans") {
+		return fmt.Errorf("the url for flamecomics must be on %s", flamecomicsURL)
+	}
 
-func (f *flamecomics) GetManga(_ context.Context) (domain.Manga, error) {
-	var manga domain.Manga
-	manga.Chapters = make(map[float32]domain.Chapter)
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
 
-	c := f.Collector.Clone()
+	switch {
+	// series and chapters of the current site, e.g. /series/2 and /series/2/<token>
+	case len(segments) >= 2 && segments[0] == "series" && flamecomicsSeriesID.MatchString(segments[1]):
+		f.seriesID = segments[1]
+	// series of the old site, e.g. /series/1719612241-solo-leveling-ragnarok/
+	case len(segments) == 2 && segments[0] == "series":
+		f.slug = flamecomicsSlugPrefix.ReplaceAllString(segments[1], "")
+	// chapters of the old site, e.g. /solo-leveling-ragnarok-chapter-1/
+	case len(segments) == 1 && flamecomicsChapterSuffix.MatchString(segments[0]):
+		f.slug = flamecomicsSlugPrefix.ReplaceAllString(flamecomicsChapterSuffix.ReplaceAllString(segments[0], ""), "")
+	default:
+		return fmt.Errorf("the url for flamecomics must point to a series, e.g. %s/series/<id>", flamecomicsURL)
+	}
 
-	c.OnHTML(".entry-title", func(e *colly.HTMLElement) {
-		manga.Title = sanitize.Filename(e.Text)
-	})
+	return nil
+}
 
-	c.OnHTML(".eplister li", func(e *colly.HTMLElement) {
-		chapterNum64, err := strconv.ParseFloat(e.Attr("data-num"), 32)
+func (f *flamecomics) GetManga(ctx context.Context) (domain.Manga, error) {
+	if len(f.seriesID) == 0 {
+		seriesID, err := f.findSeries(ctx)
 		if err != nil {
-			return
+			return domain.Manga{}, err
 		}
+		f.seriesID = seriesID
+	}
 
-		chapterURL := e.ChildAttr("a", "href")
-		chapterNum := float32(chapterNum64)
+	seriesURL := flamecomicsURL + "/series/" + f.seriesID
 
-		manga.Chapters[chapterNum] = domain.Chapter{
-			URL:      chapterURL,
-			Number:   chapterNum,
-			IsManhwa: true,
-		}
-	})
-
-	err := c.Visit(f.MangaURL)
-	if err != nil {
+	var page flamecomicsPage
+	if err := f.getNextData(ctx, seriesURL, &page); err != nil {
 		return domain.Manga{}, err
 	}
 
-	if len(manga.Title) == 0 {
+	series := page.Props.PageProps.Series
+	if len(series.Title) == 0 {
 		return domain.Manga{}, fmt.Errorf("failed to get manga for provided url: %s", f.MangaURL)
 	}
 
+	manga := domain.Manga{
+		URL:   seriesURL,
+		Title: sanitize.Filename(series.Title),
+		Metadata: domain.Metadata{
+			Synopsis:         flamecomicsText(series.Description),
+			Status:           domain.ParsePublicationStatus(series.Status),
+			ReadingDirection: domain.DirectionVertical,
+			SourceURL:        seriesURL,
+		},
+		Chapters: make(map[string][]domain.Chapter),
+	}
+
+	if len(series.Cover) != 0 {
+		manga.Metadata.CoverURL = flamecomicsCDNURL + "/series/" + f.seriesID + "/" + series.Cover
+	}
+
+	for _, title := range series.AltTitles {
+		manga.Metadata.AltTitles = appendValue(manga.Metadata.AltTitles, title)
+	}
+	for _, tag := range series.Tags {
+		manga.Metadata.Genres = appendValue(manga.Metadata.Genres, tag)
+	}
+	for _, author := range series.Author {
+		manga.Metadata.Authors = appendValue(manga.Metadata.Authors, author)
+	}
+	for _, artist := range series.Artist {
+		manga.Metadata.Artists = appendValue(manga.Metadata.Artists, artist)
+	}
+
+	for _, chapter := range page.Props.PageProps.Chapters {
+		if len(chapter.Token) == 0 || len(chapter.Chapter) == 0 {
+			continue
+		}
+
+		manga.AddChapter(domain.Chapter{
+			ID:          string(chapter.ChapterID),
+			URL:         seriesURL + "/" + chapter.Token,
+			Number:      domain.ParseChapterNumber(flamecomicsChapterNumber(string(chapter.Chapter))),
+			Title:       sanitize.Filename(strings.TrimSpace(chapter.Title)),
+			PublishedAt: chapter.ReleaseDate.Time,
+			Group:       f.String(),
+			Language:    "en",
+			IsManhwa:    true,
+		})
+	}
+
 	if len(manga.Chapters) == 0 {
 		return domain.Manga{}, fmt.Errorf("failed to get chapters for manga: %s", manga.Title)
 	}
@@ -96,27 +277,186 @@ func (f *flamecomics) GetChapters(_ context.Context, _ domain.Manga) error {
 	return nil
 }
 
-func (f *flamecomics) GetImageURLs(_ context.Context, chapter *domain.Chapter) error {
-	c := f.Collector.Clone()
+func (f *flamecomics) GetImageURLs(ctx context.Context, chapter *domain.Chapter) error {
+	var page flamecomicsPage
+	if err := f.getNextData(ctx, chapter.URL, &page); err != nil {
+		return err
+	}
+
+	data := page.Props.PageProps.Chapter
+
+	images, err := flamecomicsImages(data.Images)
+	if err != nil {
+		return fmt.Errorf("failed to parse images of chapter %s: %w", chapter.Number, err)
+	}
 
 	var imageInfos []domain.ImageInfo
+	for _, image := range images {
+		if len(image.Name) == 0 {
+			continue
+		}
+
+		imageInfos = append(imageInfos, domain.ImageInfo{
+			ImageURL: flamecomicsCDNURL + "/series/" + string(data.SeriesID) + "/" + data.Token + "/" + image.Name,
+			Width:    image.Width,
+			Height:   image.Height,
+		})
+	}
+
+	if len(imageInfos) == 0 {
+		return fmt.Errorf("failed to get image urls for chapter number: %s", chapter.Number)
+	}
+
+	chapter.ImageInfo = imageInfos
+	chapter.Headers = flamecomicsHeaders
+	return nil
+}
 
-	c.OnHTML("#readerarea img", func(e *colly.HTMLElement) {
-		imgURL := e.Attr("src")
-		if strings.HasPrefix(imgURL, "https://flamecomics") {
-			imageInfos = append(imageInfos, domain.ImageInfo{ImageURL: imgURL})
+// findSeries looks up the id of the series of an old url by comparing its slug to the titles of all series
+func (f *flamecomics) findSeries(ctx context.Context) (string, error) {
+	var browse flamecomicsBrowse
+	if err := f.getNextData(ctx, flamecomicsURL+"/browse", &browse); err != nil {
+		return "", err
+	}
+
+	if seriesID, ok := f.matchSeries(browse.Props.PageProps.Series); ok {
+		return seriesID, nil
+	}
+
+	return "", fmt.Errorf("failed to find series %q of the old url on flamecomics, use the url of the series on %s instead", f.slug, flamecomicsURL)
+}
+
+// matchSeries returns the id of the series whose title or alternative titles turn into the slug of the old url
+func (f *flamecomics) matchSeries(list []flamecomicsSeries) (string, bool) {
+	for _, series := range list {
+		for _, title := range append([]string{series.Title}, series.AltTitles...) {
+			if flamecomicsSlug(title) == f.slug && len(series.SeriesID) != 0 {
+				return string(series.SeriesID), true
+			}
 		}
-	})
+	}
+
+	return "", false
+}
 
-	err := c.Visit(chapter.URL)
+// getNextData requests the page and decodes the json the site embeds for its scripts into v
+func (f *flamecomics) getNextData(ctx context.Context, u string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "mangarr")
+
+	var body []byte
+
+	err = retry.Do(func() error {
+		resp, err := sharedhttp.ExecRequest(*f.Client, req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		body, err = io.ReadAll(resp.Body)
+		return err
+	},
+		retry.Delay(time.Second*3),
+		retry.Attempts(3),
+		retry.MaxJitter(time.Second*1),
+	)
 	if err != nil {
 		return err
 	}
 
-	if len(imageInfos) == 0 {
-		return fmt.Errorf("failed to get image urls for chapter number: %g", chapter.Number)
+	return parseNextData(body, v)
+}
+
+// parseNextData decodes the __NEXT_DATA__ json embedded in a page of a Next.js site into v
+func parseNextData(page []byte, v any) error {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(string(page)))
+	if err != nil {
+		return fmt.Errorf("failed to parse page: %w", err)
+	}
+
+	data := doc.Find("script#__NEXT_DATA__").First().Text()
+	if len(strings.TrimSpace(data)) == 0 {
+		return fmt.Errorf("failed to find __NEXT_DATA__ in page")
+	}
+
+	if err := json.Unmarshal([]byte(data), v); err != nil {
+		return fmt.Errorf("failed to parse __NEXT_DATA__: %w", err)
 	}
 
-	chapter.ImageInfo = imageInfos
 	return nil
 }
+
+// flamecomicsImages returns the pages of a chapter in order, objects indexed by page are sorted by their index
+func flamecomicsImages(raw json.RawMessage) ([]flamecomicsImage, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+
+	var images []flamecomicsImage
+	if err := json.Unmarshal(raw, &images); err == nil {
+		return images, nil
+	}
+
+	var indexed map[string]flamecomicsImage
+	if err := json.Unmarshal(raw, &indexed); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(indexed))
+	for key := range indexed {
+		keys = append(keys, key)
+	}
+
+	slices.SortFunc(keys, func(a, b string) int {
+		pageA, errA := strconv.Atoi(a)
+		pageB, errB := strconv.Atoi(b)
+		if errA != nil || errB != nil {
+			return strings.Compare(a, b)
+		}
+		return pageA - pageB
+	})
+
+	for _, key := range keys {
+		images = append(images, indexed[key])
+	}
+
+	return images, nil
+}
+
+// flamecomicsChapterNumber drops the fraction the site gives whole chapter numbers, e.g. 12.00 becomes 12, other
+// fractions are kept as written so parts like 12.1 and 12.10 stay apart
+func flamecomicsChapterNumber(number string) string {
+	if integer, fraction, ok := strings.Cut(number, "."); ok && len(strings.Trim(fraction, "0")) == 0 {
+		return integer
+	}
+
+	return number
+}
+
+// flamecomicsSlug turns a title into the slug the old site used for it
+func flamecomicsSlug(title string) string {
+	return strings.Trim(flamecomicsNonAlnum.ReplaceAllString(strings.ToLower(title), "-"), "-")
+}
+
+// flamecomicsText returns the text of a description, which is given as html
+func flamecomicsText(description string) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(description))
+	if err != nil {
+		return strings.TrimSpace(description)
+	}
+
+	var paragraphs []string
+	doc.Find("p").Each(func(_ int, p *goquery.Selection) {
+		paragraphs = appendValue(paragraphs, p.Text())
+	})
+
+	if len(paragraphs) == 0 {
+		return strings.TrimSpace(doc.Text())
+	}
+
+	return strings.Join(paragraphs, "\n")
+}
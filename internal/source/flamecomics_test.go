@@ -0,0 +1,270 @@
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"mangarr/internal/domain"
+)
+
+func flamecomicsTestPage(t *testing.T, name string, v any) {
+	t.Helper()
+
+	page, err := os.ReadFile(filepath.Join("testdata", "flamecomics", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := parseNextData(page, v); err != nil {
+		t.Fatalf("parseNextData(%s): %v", name, err)
+	}
+}
+
+func flamecomicsImageNames(images []flamecomicsImage) []string {
+	var names []string
+	for _, image := range images {
+		names = append(names, image.Name)
+	}
+	return names
+}
+
+func TestFlamecomicsParseSeries(t *testing.T) {
+	var page flamecomicsPage
+	flamecomicsTestPage(t, "series.html", &page)
+
+	series := page.Props.PageProps.Series
+	if series.Title != "Solo Leveling: Ragnarok" {
+		t.Errorf("Title = %q, want %q", series.Title, "Solo Leveling: Ragnarok")
+	}
+	if series.SeriesID != "2" {
+		t.Errorf("SeriesID = %q, want 2", series.SeriesID)
+	}
+	// alternative titles are given as json encoded list, authors as comma separated string
+	if !slices.Equal(series.AltTitles, []string{"나 혼자만 레벨업: 라그나로크", "Solo Leveling 2"}) {
+		t.Errorf("AltTitles = %q", series.AltTitles)
+	}
+	if len(series.Author) != 2 {
+		t.Errorf("Author = %q, want 2 authors", series.Author)
+	}
+	if got := flamecomicsText(series.Description); got != "The gates are opening again.\nSung Suho must protect the world his father saved." {
+		t.Errorf("description = %q", got)
+	}
+
+	want := []struct {
+		token  string
+		number string
+		date   time.Time
+	}{
+		{token: "c3d4e5f60718293a", number: "2.50", date: time.Unix(1723680000, 0)},
+		{token: "b2c3d4e5f6071829", number: "2", date: time.Unix(1723075200, 0)},
+		{token: "a1b2c3d4e5f60718", number: "1", date: time.Unix(1722470400, 0)},
+	}
+
+	chapters := page.Props.PageProps.Chapters
+	if len(chapters) != len(want) {
+		t.Fatalf("chapters = %d, want %d", len(chapters), len(want))
+	}
+
+	for i, chapter := range chapters {
+		if chapter.Token != want[i].token {
+			t.Errorf("chapter %d: Token = %q, want %q", i, chapter.Token, want[i].token)
+		}
+		if got := flamecomicsChapterNumber(string(chapter.Chapter)); got != want[i].number {
+			t.Errorf("chapter %d: number = %q, want %q", i, got, want[i].number)
+		}
+		// release dates are given as number or string
+		if !chapter.ReleaseDate.Equal(want[i].date) {
+			t.Errorf("chapter %d: ReleaseDate = %s, want %s", i, chapter.ReleaseDate, want[i].date)
+		}
+	}
+}
+
+func TestFlamecomicsParseBrowse(t *testing.T) {
+	var browse flamecomicsBrowse
+	flamecomicsTestPage(t, "browse.html", &browse)
+
+	var ids []string
+	for _, series := range browse.Props.PageProps.Series {
+		ids = append(ids, string(series.SeriesID))
+	}
+
+	// series ids are given as number or string
+	if want := []string{"1", "2", "3", "7"}; !slices.Equal(ids, want) {
+		t.Errorf("series ids = %v, want %v", ids, want)
+	}
+}
+
+func TestFlamecomicsParseChapter(t *testing.T) {
+	var page flamecomicsPage
+	flamecomicsTestPage(t, "chapter.html", &page)
+
+	chapter := page.Props.PageProps.Chapter
+	if chapter.Token != "a1b2c3d4e5f60718" || chapter.SeriesID != "2" {
+		t.Errorf("chapter = %s of series %s", chapter.Token, chapter.SeriesID)
+	}
+
+	images, err := flamecomicsImages(chapter.Images)
+	if err != nil {
+		t.Fatalf("flamecomicsImages: %v", err)
+	}
+
+	var want []string
+	for i := 1; i <= 11; i++ {
+		want = append(want, fmt.Sprintf("%03d.webp", i))
+	}
+
+	if got := flamecomicsImageNames(images); !slices.Equal(got, want) {
+		t.Errorf("images = %v, want %v", got, want)
+	}
+}
+
+func TestParseNextDataMissing(t *testing.T) {
+	page := []byte(`<!DOCTYPE html><html><head><title>Just a moment...</title></head><body></body></html>`)
+
+	var v flamecomicsPage
+	if err := parseNextData(page, &v); err == nil {
+		t.Error("parseNextData of a page without __NEXT_DATA__ should fail")
+	}
+}
+
+func TestFlamecomicsImages(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want []string
+	}{
+		{
+			name: "list",
+			raw:  `[{"name":"01.jpg"},{"name":"02.jpg"},{"name":"03.jpg"}]`,
+			want: []string{"01.jpg", "02.jpg", "03.jpg"},
+		},
+		{
+			name: "indexed by page",
+			raw:  `{"2":{"name":"03.jpg"},"10":{"name":"11.jpg"},"0":{"name":"01.jpg"},"1":{"name":"02.jpg"}}`,
+			want: []string{"01.jpg", "02.jpg", "03.jpg", "11.jpg"},
+		},
+		{name: "null", raw: `null`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			images, err := flamecomicsImages(json.RawMessage(tt.raw))
+			if err != nil {
+				t.Fatalf("flamecomicsImages: %v", err)
+			}
+
+			if got := flamecomicsImageNames(images); !slices.Equal(got, tt.want) {
+				t.Errorf("images = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlamecomicsChapterNumber(t *testing.T) {
+	tests := map[string]string{
+		"12.50": "12.50",
+		"12.10": "12.10",
+		"12.00": "12",
+		"10.05": "10.05",
+		"100":   "100",
+	}
+
+	for number, want := range tests {
+		if got := flamecomicsChapterNumber(number); got != want {
+			t.Errorf("flamecomicsChapterNumber(%q) = %q, want %q", number, got, want)
+		}
+	}
+}
+
+func TestFlamecomicsValidateInput(t *testing.T) {
+	tests := []struct {
+		input    string
+		seriesID string
+		slug     string
+		wantErr  bool
+	}{
+		{input: "https://flamecomics.xyz/series/2", seriesID: "2"},
+		{input: "https://flamecomics.xyz/series/2/a1b2c3d4e5f60718", seriesID: "2"},
+		{input: "2", seriesID: "2"},
+		{input: "https://flamecomics.com/series/1719612241-solo-leveling-ragnarok/", slug: "solo-leveling-ragnarok"},
+		{input: "https://flamecomics.com/solo-leveling-ragnarok-chapter-1/", slug: "solo-leveling-ragnarok"},
+		{input: "https://flamescans.org/1719612241-solo-leveling-ragnarok-chapter-12-5/", slug: "solo-leveling-ragnarok"},
+		{input: "https://flamecomics.xyz/browse", wantErr: true},
+		{input: "https://asuracomic.net/series/2", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			f := &flamecomics{MangaURL: tt.input}
+
+			err := f.ValidateInput()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if f.seriesID != tt.seriesID || f.slug != tt.slug {
+				t.Errorf("series id = %q, slug = %q, want %q, %q", f.seriesID, f.slug, tt.seriesID, tt.slug)
+			}
+		})
+	}
+}
+
+func TestFlamecomicsMatchSeries(t *testing.T) {
+	var browse flamecomicsBrowse
+	flamecomicsTestPage(t, "browse.html", &browse)
+
+	tests := []struct {
+		slug     string
+		seriesID string
+	}{
+		{slug: "solo-leveling-ragnarok", seriesID: "2"},
+		// titles that are the start of another title must match exactly
+		{slug: "solo-leveling", seriesID: "3"},
+		{slug: "omniscient-reader-s-viewpoint", seriesID: "1"},
+		{slug: "return-of-the-mad-demon", seriesID: "7"},
+		{slug: "the-beginning-after-the-end"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.slug, func(t *testing.T) {
+			f := &flamecomics{slug: tt.slug}
+
+			seriesID, ok := f.matchSeries(browse.Props.PageProps.Series)
+			if ok != (len(tt.seriesID) != 0) || seriesID != tt.seriesID {
+				t.Errorf("matchSeries = %q, %v, want %q", seriesID, ok, tt.seriesID)
+			}
+		})
+	}
+}
+
+func TestFlamecomicsGetImageURLs(t *testing.T) {
+	server := httptest.NewServer(http.FileServer(http.Dir(filepath.Join("testdata", "flamecomics"))))
+	defer server.Close()
+
+	f := NewFlamecomics("https://flamecomics.xyz/series/2")
+
+	chapter := domain.Chapter{URL: server.URL + "/chapter.html", Number: domain.ParseChapterNumber("1")}
+	if err := f.GetImageURLs(context.Background(), &chapter); err != nil {
+		t.Fatalf("GetImageURLs: %v", err)
+	}
+
+	if len(chapter.ImageInfo) != 11 {
+		t.Fatalf("pages = %d, want 11", len(chapter.ImageInfo))
+	}
+	if want := flamecomicsCDNURL + "/series/2/a1b2c3d4e5f60718/001.webp"; chapter.ImageInfo[0].ImageURL != want {
+		t.Errorf("page 1 = %q, want %q", chapter.ImageInfo[0].ImageURL, want)
+	}
+	if last := chapter.ImageInfo[10]; last.ImageURL != flamecomicsCDNURL+"/series/2/a1b2c3d4e5f60718/011.webp" || last.Height != 900 {
+		t.Errorf("page 11 = %+v", last)
+	}
+	if chapter.Headers["Referer"] != flamecomicsURL+"/" {
+		t.Errorf("Referer = %q, the image server rejects requests without it", chapter.Headers["Referer"])
+	}
+}
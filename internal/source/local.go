@@ -0,0 +1,335 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"mangarr/internal/domain"
+	"mangarr/internal/sanitize"
+	"mangarr/internal/utils"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// local options
+const (
+	localOptionPattern = "pattern"
+	localOptionSeries  = "series"
+)
+
+// localSettleTime is how long the inbox has to be quiet after a change before it is imported, so files that are
+// still being copied aren't picked up halfway
+const localSettleTime = 10 * time.Second
+
+// defaultLocalPattern matches names like "Blue Lock Chapter 250", "Blue Lock ch. 250" and "Blue_Lock_c250" as well as
+// chapters in a folder named after the series like "Blue Lock/Chapter 250", the chapter keyword has to start a word
+// and a bare c has to be followed by the number so series like "Fantastic 4" and "Magic" aren't split
+var defaultLocalPattern = regexp.MustCompile(`(?i)(?:^|/)(?P<series>[^/]+?)(?:/|[\s_.-]+)(?:chapter[\s_.]*|ch\.?[\s_]*|c)(?P<chapter>\d+(?:\.\d+)?[a-z]?)[^/]*$`)
+
+type local struct {
+	Inbox    string
+	Pattern  string
+	Series   string
+	Language string
+
+	pattern *regexp.Regexp
+}
+
+func init() {
+	Register(Definition{
+		Key:  "local",
+		Name: "Local folder",
+		Fields: []Field{
+			{Name: InputManga, Required: true, Help: "Inbox directory holding CBZ or ZIP archives and image folders of chapters", Example: "/data/inbox"},
+			{Name: InputLanguage, Help: "Language of the chapters"},
+		},
+		Options: []Option{
+			{Name: localOptionPattern, Help: "Regex matched against the paths relative to the inbox without extension, the named groups series and chapter are required, volume and title are optional", Example: `(?P<series>[^/]+)/Vol\.(?P<volume>\d+) Ch\.(?P<chapter>\d+)`},
+			{Name: localOptionSeries, Help: "Series to import, required if the inbox holds more than one", Example: "Solo Leveling"},
+		},
+		New: func(in Input) domain.Source {
+			return NewLocal(in)
+		},
+	})
+}
+
+func NewLocal(in Input) domain.Source {
+	return &local{
+		Inbox:    in.Manga,
+		Pattern:  in.Option(localOptionPattern),
+		Series:   in.Option(localOptionSeries),
+		Language: in.Language,
+	}
+}
+
+func (l *local) String() string {
+	return "Local"
+}
+
+func (l *local) ValidateInput() error {
+	info, err := os.Stat(l.Inbox)
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		return fmt.Errorf("inbox %s is not a directory", l.Inbox)
+	}
+
+	l.pattern = defaultLocalPattern
+	if len(l.Pattern) != 0 {
+		pattern, err := regexp.Compile(l.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+		l.pattern = pattern
+	}
+
+	for _, group := range []string{"series", "chapter"} {
+		if l.pattern.SubexpIndex(group) < 0 {
+			return fmt.Errorf("pattern is missing the named group %s", group)
+		}
+	}
+
+	return nil
+}
+
+func (l *local) GetManga(_ context.Context) (domain.Manga, error) {
+	var series []string
+
+	manga := domain.Manga{
+		URL: l.Inbox,
+		Metadata: domain.Metadata{
+			SourceURL: l.Inbox,
+		},
+		Chapters: make(map[string][]domain.Chapter),
+	}
+
+	err := filepath.WalkDir(l.Inbox, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if path == l.Inbox {
+			return nil
+		}
+
+		var archiveURL string
+		switch {
+		case !d.IsDir() && isArchiveFile(path):
+			archiveURL = fileURL(path)
+		case d.IsDir() && hasImages(path):
+		default:
+			return nil
+		}
+
+		rel, err := filepath.Rel(l.Inbox, path)
+		if err != nil {
+			return err
+		}
+
+		name := strings.TrimSuffix(filepath.ToSlash(rel), filepath.Ext(rel))
+		if d.IsDir() {
+			name = filepath.ToSlash(rel)
+		}
+
+		matches := l.pattern.FindStringSubmatch(name)
+		if matches == nil {
+			return nil
+		}
+
+		seriesName := strings.TrimSpace(l.group(matches, "series"))
+		if len(l.Series) != 0 && !sameSeries(seriesName, l.Series) {
+			return nil
+		}
+
+		if !slices.ContainsFunc(series, func(s string) bool { return sameSeries(s, seriesName) }) {
+			series = append(series, seriesName)
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		manga.AddChapter(domain.Chapter{
+			ID:          path,
+			URL:         fileURL(path),
+			ArchiveURL:  archiveURL,
+			Local:       true,
+			Number:      domain.ParseChapterNumber(l.group(matches, "chapter")),
+			Volume:      l.group(matches, "volume"),
+			Title:       sanitize.Filename(l.group(matches, "title")),
+			PublishedAt: info.ModTime(),
+			Group:       l.String(),
+			Language:    l.Language,
+		})
+
+		return nil
+	})
+	if err != nil {
+		return domain.Manga{}, err
+	}
+
+	switch {
+	case len(series) == 0:
+		return domain.Manga{}, fmt.Errorf("failed to find chapters in inbox: %s", l.Inbox)
+	case len(series) > 1:
+		return domain.Manga{}, fmt.Errorf("inbox %s holds more than one series, set the series option to one of: %s", l.Inbox, strings.Join(series, ", "))
+	}
+
+	manga.Title = sanitize.Filename(series[0])
+	if len(l.Series) != 0 {
+		manga.Title = sanitize.Filename(l.Series)
+	}
+
+	return manga, nil
+}
+
+func (l *local) GetChapters(_ context.Context, _ domain.Manga) error {
+	return nil
+}
+
+func (l *local) GetImageURLs(_ context.Context, chapter *domain.Chapter) error {
+	// the archive is extracted as is
+	if len(chapter.ArchiveURL) != 0 {
+		return nil
+	}
+
+	dir := strings.TrimPrefix(chapter.URL, "file://")
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	// pages are often named without padding, e.g. "1.jpg" to "10.jpg"
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int {
+		return utils.NaturalCompare(a.Name(), b.Name())
+	})
+
+	var imageInfos []domain.ImageInfo
+	for _, entry := range entries {
+		if !entry.IsDir() && isImage(entry.Name()) {
+			imageInfos = append(imageInfos, domain.ImageInfo{ImageURL: fileURL(filepath.Join(dir, entry.Name()))})
+		}
+	}
+
+	if len(imageInfos) == 0 {
+		return fmt.Errorf("failed to get image urls for chapter number: %s", chapter.Number)
+	}
+
+	chapter.ImageInfo = imageInfos
+	return nil
+}
+
+// Watch calls changed whenever the inbox has settled after files were added to it
+func (l *local) Watch(ctx context.Context, changed func()) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	// folders of images are filled after they are created, so they are watched as well
+	err = filepath.WalkDir(l.Inbox, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return err
+		}
+		return watcher.Add(path)
+	})
+	if err != nil {
+		return err
+	}
+
+	settle := time.NewTimer(localSettleTime)
+	settle.Stop()
+	defer settle.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+
+			if event.Has(fsnotify.Create) {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					_ = watcher.Add(event.Name)
+				}
+			}
+
+			settle.Reset(localSettleTime)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			return err
+		case <-settle.C:
+			changed()
+		}
+	}
+}
+
+func (l *local) group(matches []string, name string) string {
+	i := l.pattern.SubexpIndex(name)
+	if i < 0 || i >= len(matches) {
+		return ""
+	}
+
+	return strings.TrimSpace(matches[i])
+}
+
+// sameSeries compares series names ignoring case and the separators file names use instead of spaces
+func sameSeries(a, b string) bool {
+	normalize := strings.NewReplacer("_", " ", ".", " ")
+	return strings.EqualFold(strings.Join(strings.Fields(normalize.Replace(a)), " "), strings.Join(strings.Fields(normalize.Replace(b)), " "))
+}
+
+// hasImages reports whether the directory directly contains images
+func hasImages(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+
+	return slices.ContainsFunc(entries, func(entry fs.DirEntry) bool {
+		return !entry.IsDir() && isImage(entry.Name())
+	})
+}
+
+func isArchiveFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".cbz", ".zip":
+		return true
+	default:
+		return false
+	}
+}
+
+func isImage(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".jpg", ".jpeg", ".png", ".gif", ".webp":
+		return true
+	default:
+		return false
+	}
+}
+
+func fileURL(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = path
+	}
+
+	return "file://" + filepath.ToSlash(abs)
+}
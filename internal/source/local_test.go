@@ -0,0 +1,119 @@
+package source
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDefaultLocalPattern(t *testing.T) {
+	tests := []struct {
+		name    string
+		series  string
+		chapter string
+	}{
+		{name: "Blue Lock Chapter 250", series: "Blue Lock", chapter: "250"},
+		{name: "Blue Lock/Chapter 250", series: "Blue Lock", chapter: "250"},
+		{name: "Blue Lock/Blue Lock Chapter 251", series: "Blue Lock", chapter: "251"},
+		{name: "Fantastic 4 Chapter 12", series: "Fantastic 4", chapter: "12"},
+		{name: "Magic 2 Ch 5", series: "Magic 2", chapter: "5"},
+		{name: "Magic ch. 7", series: "Magic", chapter: "7"},
+		{name: "Solo Leveling c012", series: "Solo Leveling", chapter: "012"},
+		{name: "Solo_Leveling_-_c012 [Group]", series: "Solo_Leveling", chapter: "012"},
+		{name: "Solo Leveling - Chapter 10.5", series: "Solo Leveling", chapter: "10.5"},
+		{name: "One Piece Chapter 1000a", series: "One Piece", chapter: "1000a"},
+		{name: "Chainsaw Man ch.150", series: "Chainsaw Man", chapter: "150"},
+		{name: "Fantastic 4", series: ""},
+		{name: "Chapter 12", series: ""},
+		{name: "Dragon Ball c 12", series: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &local{pattern: defaultLocalPattern}
+
+			matches := l.pattern.FindStringSubmatch(tt.name)
+			if len(tt.series) == 0 {
+				if matches != nil {
+					t.Fatalf("matched series %q chapter %q, want no match", l.group(matches, "series"), l.group(matches, "chapter"))
+				}
+				return
+			}
+
+			if matches == nil {
+				t.Fatal("no match")
+			}
+			if got := l.group(matches, "series"); got != tt.series {
+				t.Errorf("series = %q, want %q", got, tt.series)
+			}
+			if got := l.group(matches, "chapter"); got != tt.chapter {
+				t.Errorf("chapter = %q, want %q", got, tt.chapter)
+			}
+		})
+	}
+}
+
+func TestLocalGetManga(t *testing.T) {
+	inbox := t.TempDir()
+
+	for _, path := range []string{
+		"Blue Lock/Chapter 250/001.jpg",
+		"Blue Lock/Chapter 250/2.jpg",
+		"Blue Lock/Chapter 250/10.jpg",
+		"Blue Lock/Blue Lock Chapter 251.cbz",
+		"Blue Lock Chapter 252.cbz",
+		"notes.txt",
+	} {
+		path = filepath.Join(inbox, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := NewLocal(Input{Manga: inbox})
+	if err := s.ValidateInput(); err != nil {
+		t.Fatalf("ValidateInput: %v", err)
+	}
+
+	manga, err := s.GetManga(context.Background())
+	if err != nil {
+		t.Fatalf("GetManga: %v", err)
+	}
+
+	if manga.Title != "Blue Lock" {
+		t.Errorf("Title = %q, want %q", manga.Title, "Blue Lock")
+	}
+
+	for _, key := range []string{"250", "251", "252"} {
+		if len(manga.Chapters[key]) != 1 {
+			t.Errorf("releases of chapter %s = %d, want 1", key, len(manga.Chapters[key]))
+		}
+	}
+
+	folder := manga.Chapters["250"][0]
+	if !folder.Local {
+		t.Error("image folder is not marked as local")
+	}
+	if len(folder.ArchiveURL) != 0 {
+		t.Errorf("image folder has archive url %q", folder.ArchiveURL)
+	}
+	if err := s.GetImageURLs(context.Background(), &folder); err != nil {
+		t.Fatalf("GetImageURLs: %v", err)
+	}
+	if len(folder.ImageInfo) != 3 {
+		t.Fatalf("images of chapter 250 = %d, want 3", len(folder.ImageInfo))
+	}
+	for i, want := range []string{"001.jpg", "2.jpg", "10.jpg"} {
+		if got := filepath.Base(folder.ImageInfo[i].ImageURL); got != want {
+			t.Errorf("page %d = %s, want %s", i+1, got, want)
+		}
+	}
+
+	if archive := manga.Chapters["251"][0]; len(archive.ArchiveURL) == 0 {
+		t.Error("archive of chapter 251 has no archive url")
+	}
+}
@@ -2,12 +2,15 @@ package source
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
-	"strconv"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"mangarr/internal/domain"
@@ -19,15 +22,61 @@ import (
 )
 
 const (
-	mangadexURL   = "https://api.mangadex.org"
-	mangadexLimit = 500
+	mangadexURL         = "https://api.mangadex.org"
+	mangadexSiteURL     = "https://mangadex.org"
+	mangadexUploadsURL  = "https://uploads.mangadex.org"
+	mangadexLimit       = 500
+	mangadexSearchLimit = 20
+	mangadexReportURL   = "https://api.mangadex.network/report"
+	// mangadexReportTimeout is how long a report may take before it is given up
+	mangadexReportTimeout = 5 * time.Second
+	// mangadexReportDeadline is how long a report may wait in the queue before it is given up
+	mangadexReportDeadline = 30 * time.Second
+	// mangadexReportWorkers is how many image reports are sent at the same time
+	mangadexReportWorkers = 4
 )
 
+var (
+	// mangadexLimiter keeps requests to the api below its global rate limit
+	mangadexLimiter = sharedhttp.NewLimiter(5, time.Second)
+	// mangadexAtHomeLimiter keeps requests for at-home servers below their stricter rate limit
+	mangadexAtHomeLimiter = sharedhttp.NewLimiter(40, time.Minute)
+	// mangadexReports queues the image reports until one of the report workers sends them
+	mangadexReports     = make(chan mangadexQueuedReport, 256)
+	mangadexReportsOnce sync.Once
+)
+
+// mangadex options
+const (
+	mangadexOptionQuality = "quality"
+)
+
+// image qualities served by the at-home network
+const (
+	mangadexQualityData      = "data"
+	mangadexQualityDataSaver = "data-saver"
+)
+
+// mangadexAnyGroup accepts chapters of every group that isn't blocked
+const mangadexAnyGroup = "any"
+
 type mangadex struct {
 	MangaID  string
-	GroupID  string
+	Group    string
 	Language string
+	Quality  string
 	Client   *http.Client
+
+	groups    mangadexGroups
+	languages []string
+}
+
+// mangadexGroups holds the groups whose chapters are used, parsed from a comma separated list of group ids in order
+// of priority, "any" to accept all other groups as well and group ids prefixed with "!" to block them
+type mangadexGroups struct {
+	Priority []string
+	Any      bool
+	Blocked  []string
 }
 
 type mangadexManga struct {
@@ -35,10 +84,26 @@ type mangadexManga struct {
 		ID         string `json:"id"`
 		Type       string `json:"type"`
 		Attributes struct {
-			Title struct {
-				En string `json:"en"`
-			} `json:"title"`
+			Title            map[string]string   `json:"title"`
+			AltTitles        []map[string]string `json:"altTitles"`
+			Description      map[string]string   `json:"description"`
+			OriginalLanguage string              `json:"originalLanguage"`
+			Status           string              `json:"status"`
+			Tags             []struct {
+				Attributes struct {
+					Name  map[string]string `json:"name"`
+					Group string            `json:"group"`
+				} `json:"attributes"`
+			} `json:"tags"`
 		} `json:"attributes"`
+		Relationships []struct {
+			ID         string `json:"id"`
+			Type       string `json:"type"`
+			Attributes struct {
+				Name     string `json:"name"`
+				FileName string `json:"fileName"`
+			} `json:"attributes"`
+		} `json:"relationships"`
 	} `json:"data"`
 }
 
@@ -47,18 +112,27 @@ type mangadexChapters struct {
 		ID         string `json:"id"`
 		Type       string `json:"type"`
 		Attributes struct {
-			Volume  *string `json:"volume"`
-			Chapter string  `json:"chapter"`
-			Title   *string `json:"title"`
+			Volume             *string   `json:"volume"`
+			Chapter            string    `json:"chapter"`
+			Title              *string   `json:"title"`
+			TranslatedLanguage string    `json:"translatedLanguage"`
+			Pages              int       `json:"pages"`
+			ExternalURL        *string   `json:"externalUrl"`
+			PublishAt          time.Time `json:"publishAt"`
 		} `json:"attributes"`
-		Relationships []struct {
-			ID   string `json:"id"`
-			Type string `json:"type"`
-		} `json:"relationships"`
+		Relationships []mangadexRelationship `json:"relationships"`
 	} `json:"data"`
 	Total int `json:"total"`
 }
 
+type mangadexRelationship struct {
+	ID         string `json:"id"`
+	Type       string `json:"type"`
+	Attributes struct {
+		Name string `json:"name"`
+	} `json:"attributes"`
+}
+
 type mangadexChapter struct {
 	BaseURL string `json:"baseUrl"`
 	Chapter struct {
@@ -68,7 +142,52 @@ type mangadexChapter struct {
 	} `json:"chapter"`
 }
 
-func NewMangadex(manga, group, language string) domain.Source {
+// mangadexReport is the report of an image fetched from an at-home server
+type mangadexReport struct {
+	URL      string `json:"url"`
+	Success  bool   `json:"success"`
+	Bytes    int64  `json:"bytes"`
+	Duration int64  `json:"duration"`
+	Cached   bool   `json:"cached"`
+}
+
+type mangadexSearch struct {
+	Data []struct {
+		ID         string `json:"id"`
+		Attributes struct {
+			Title                        map[string]string `json:"title"`
+			AvailableTranslatedLanguages []string          `json:"availableTranslatedLanguages"`
+		} `json:"attributes"`
+		Relationships []struct {
+			ID         string `json:"id"`
+			Type       string `json:"type"`
+			Attributes struct {
+				FileName string `json:"fileName"`
+			} `json:"attributes"`
+		} `json:"relationships"`
+	} `json:"data"`
+}
+
+func init() {
+	Register(Definition{
+		Key:  "mangadex",
+		Name: "MangaDex",
+		Fields: []Field{
+			{Name: InputManga, Required: true, Help: "UUID of the manga on MangaDex", Example: "801513ba-a712-498c-8f57-cae55b38cc92"},
+			{Name: InputGroup, Required: true, Help: `Comma separated UUIDs of the scanlation groups on MangaDex in order of priority, "any" accepts all other groups and a "!" in front of a UUID blocks the group`, Example: "277df5c9-a486-40f6-8dfa-c086c6b60935,any,!a0bc5d8e-5a2b-4ea4-9c5e-b6e2ebb2f9b8"},
+			{Name: InputLanguage, Help: `Comma separated language codes of the chapters in order of preference, defaults to "en"`, Example: "en,en-us,es-la"},
+		},
+		Options: []Option{
+			{Name: mangadexOptionQuality, Help: `Quality of the images, "data" for the original images or "data-saver" for compressed ones, defaults to "data"`, Example: mangadexQualityDataSaver},
+		},
+		RateLimit: "5 requests per second, 40 per minute for the image servers",
+		New: func(in Input) domain.Source {
+			return NewMangadex(in.Manga, in.Group, in.Language, in.Option(mangadexOptionQuality))
+		},
+	})
+}
+
+func NewMangadex(manga, group, language, quality string) domain.Source {
 	client := http.Client{
 		Timeout:   60 * time.Second,
 		Transport: sharedhttp.Transport,
@@ -76,8 +195,9 @@ func NewMangadex(manga, group, language string) domain.Source {
 
 	return &mangadex{
 		MangaID:  manga,
-		GroupID:  group,
+		Group:    group,
 		Language: language,
+		Quality:  quality,
 		Client:   &client,
 	}
 }
@@ -88,15 +208,33 @@ func (m *mangadex) String() string {
 
 func (m *mangadex) ValidateInput() error {
 	if _, err := uuid.Parse(m.MangaID); err != nil {
-		return fmt.Errorf("invalid mangaplus manga id: %w", err)
+		return fmt.Errorf("invalid mangadex manga id: %w", err)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(m.Quality)) {
+	case "", mangadexQualityData:
+		m.Quality = mangadexQualityData
+	case mangadexQualityDataSaver, "datasaver":
+		m.Quality = mangadexQualityDataSaver
+	default:
+		return fmt.Errorf("invalid mangadex quality %q, must be one of: %s, %s", m.Quality, mangadexQualityData, mangadexQualityDataSaver)
 	}
 
-	if _, err := uuid.Parse(m.GroupID); err != nil {
-		return fmt.Errorf("invalid mangaplus group id: %w", err)
+	groups, err := parseMangadexGroups(m.Group)
+	if err != nil {
+		return err
 	}
+	m.groups = groups
 
-	if len(m.Language) == 0 {
-		m.Language = "en"
+	m.languages = nil
+	for _, language := range strings.Split(m.Language, ",") {
+		if language = strings.ToLower(strings.TrimSpace(language)); len(language) != 0 && !slices.Contains(m.languages, language) {
+			m.languages = append(m.languages, language)
+		}
+	}
+
+	if len(m.languages) == 0 {
+		m.languages = []string{"en"}
 	}
 
 	return nil
@@ -105,62 +243,155 @@ func (m *mangadex) ValidateInput() error {
 func (m *mangadex) GetManga(ctx context.Context) (domain.Manga, error) {
 	var mangaResp mangadexManga
 
+	params := url.Values{
+		"includes[]": []string{"author", "artist", "cover_art"},
+	}
+
 	path, err := url.JoinPath(mangadexURL, "manga", m.MangaID)
 	if err != nil {
 		return domain.Manga{}, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
+	u, err := url.Parse(path)
 	if err != nil {
-		return domain.Manga{}, fmt.Errorf("failed to create request: %w", err)
+		return domain.Manga{}, err
 	}
 
-	req.Header.Set("User-Agent", "mangarr")
+	u.RawQuery = params.Encode()
 
-	retryErr := retry.Do(func() error {
-		resp, err := sharedhttp.ExecRequest(*m.Client, req)
-		if err != nil {
-			return err
+	if err := m.get(ctx, mangadexLimiter, u.String(), &mangaResp); err != nil {
+		return domain.Manga{}, err
+	}
+
+	title := m.getTitle(mangaResp)
+	if len(title) == 0 {
+		return domain.Manga{}, fmt.Errorf("failed to get manga for id: %s", m.MangaID)
+	}
+
+	return domain.Manga{
+		Title:    sanitize.Filename(title),
+		Metadata: m.getMetadata(mangaResp),
+		Chapters: make(map[string][]domain.Chapter),
+	}, nil
+}
+
+// getTitle returns the title of the manga in the preferred languages, falling back to english and romanized japanese
+// titles, the main title is preferred over the alternative titles in the same language
+func (m *mangadex) getTitle(mangaResp mangadexManga) string {
+	attributes := mangaResp.Data.Attributes
+
+	for _, language := range append(slices.Clone(m.languages), "en", "ja-ro") {
+		if title := attributes.Title[language]; len(title) != 0 {
+			return title
 		}
 
-		buf := bufio.NewReader(resp.Body)
+		for _, altTitle := range attributes.AltTitles {
+			if title := altTitle[language]; len(title) != 0 {
+				return title
+			}
+		}
+	}
 
-		err = json.NewDecoder(buf).Decode(&mangaResp)
-		if err != nil {
-			return retry.Unrecoverable(err)
+	// fall back to the main title in any language
+	for _, title := range attributes.Title {
+		if len(title) != 0 {
+			return title
 		}
+	}
 
-		return nil
-	},
-		retry.Delay(time.Second*3),
-		retry.Attempts(3),
-		retry.MaxJitter(time.Second*1),
-	)
+	return ""
+}
 
-	title := mangaResp.Data.Attributes.Title.En
-	if len(title) == 0 {
-		return domain.Manga{}, fmt.Errorf("failed to get manga for id: %s", m.MangaID)
+// localized returns the value in the most preferred language, falling back to english
+func (m *mangadex) localized(values map[string]string) string {
+	for _, language := range append(slices.Clone(m.languages), "en") {
+		if value := values[language]; len(value) != 0 {
+			return value
+		}
 	}
 
-	return domain.Manga{
-		Title:    sanitize.Filename(title),
-		Chapters: make(map[float32]domain.Chapter),
-	}, retryErr
+	return ""
+}
+
+// getMetadata collects the series metadata from the manga response
+func (m *mangadex) getMetadata(mangaResp mangadexManga) domain.Metadata {
+	attributes := mangaResp.Data.Attributes
+
+	metadata := domain.Metadata{
+		Synopsis:  m.localized(attributes.Description),
+		Status:    domain.ParsePublicationStatus(attributes.Status),
+		SourceURL: mangadexSiteURL + "/title/" + mangaResp.Data.ID,
+	}
+
+	for _, altTitle := range attributes.AltTitles {
+		for _, title := range altTitle {
+			metadata.AltTitles = append(metadata.AltTitles, title)
+		}
+	}
+
+	longStrip := false
+	for _, tag := range attributes.Tags {
+		name := tag.Attributes.Name["en"]
+
+		switch tag.Attributes.Group {
+		case "genre":
+			metadata.Genres = append(metadata.Genres, name)
+		default:
+			metadata.Tags = append(metadata.Tags, name)
+		}
+
+		if name == "Long Strip" {
+			longStrip = true
+		}
+	}
+
+	for _, rel := range mangaResp.Data.Relationships {
+		switch rel.Type {
+		case "author":
+			metadata.Authors = append(metadata.Authors, rel.Attributes.Name)
+		case "artist":
+			metadata.Artists = append(metadata.Artists, rel.Attributes.Name)
+		case "cover_art":
+			metadata.CoverURL = mangadexUploadsURL + "/covers/" + mangaResp.Data.ID + "/" + rel.Attributes.FileName
+		}
+	}
+
+	switch {
+	case longStrip:
+		metadata.ReadingDirection = domain.DirectionVertical
+	case attributes.OriginalLanguage == "ja":
+		metadata.ReadingDirection = domain.DirectionRightToLeft
+	default:
+		metadata.ReadingDirection = domain.DirectionLeftToRight
+	}
+
+	return metadata
 }
 
 func (m *mangadex) GetChapters(ctx context.Context, manga domain.Manga) error {
-	var errFunc error
-	var chapterResp mangadexChapters
 	var chapterCount int
 	var offset int
 
+	// ranks holds the priority of the language and group of every chapter by its id
+	ranks := make(map[string]int)
+	groupRanks := len(m.groups.Priority) + 1
+
 	for {
+		var chapterResp mangadexChapters
+
 		params := url.Values{
-			"translatedLanguage[]": []string{m.Language},
+			"translatedLanguage[]": m.languages,
+			"includes[]":           []string{"scanlation_group"},
 			"order[volume]":        []string{"desc"},
 			"order[chapter]":       []string{"desc"},
 			"limit":                []string{fmt.Sprintf("%d", mangadexLimit)},
 			"offset":               []string{fmt.Sprintf("%d", offset)},
+			"includeExternalUrl":   []string{"0"},
+			"includeEmptyPages":    []string{"0"},
+		}
+
+		if len(m.groups.Blocked) != 0 {
+			params["excludedGroups[]"] = m.groups.Blocked
 		}
 
 		path, err := url.JoinPath(mangadexURL, "manga", m.MangaID, "feed")
@@ -175,68 +406,84 @@ func (m *mangadex) GetChapters(ctx context.Context, manga domain.Manga) error {
 
 		u.RawQuery = params.Encode()
 
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
-		if err != nil {
-			return fmt.Errorf("failed to create request: %w", err)
+		if err := m.get(ctx, mangadexLimiter, u.String(), &chapterResp); err != nil {
+			return err
 		}
 
-		req.Header.Set("User-Agent", "mangarr")
+		for _, data := range chapterResp.Data {
+			// chapters hosted on other sites have no pages on mangadex
+			if data.Attributes.ExternalURL != nil || data.Attributes.Pages == 0 {
+				continue
+			}
 
-		errFunc = retry.Do(func() error {
-			resp, err := sharedhttp.ExecRequest(*m.Client, req)
-			if err != nil {
-				return err
+			rank, group, ok := m.groups.rank(data.Relationships)
+			if !ok {
+				continue
 			}
 
-			buf := bufio.NewReader(resp.Body)
+			// chapters without a number are oneshots
+			label := data.Attributes.Chapter
+			if len(label) == 0 {
+				label = "Oneshot"
+			}
+			chapterNum := domain.ParseChapterNumber(label)
 
-			err = json.NewDecoder(buf).Decode(&chapterResp)
-			if err != nil {
-				return retry.Unrecoverable(err)
+			var title string
+			if data.Attributes.Title != nil {
+				title = *data.Attributes.Title
 			}
 
-			return nil
-		},
-			retry.Delay(time.Second*3),
-			retry.Attempts(3),
-			retry.MaxJitter(time.Second*1),
-		)
+			var volume string
+			if data.Attributes.Volume != nil {
+				volume = *data.Attributes.Volume
+			}
 
-		for _, data := range chapterResp.Data {
-			for _, rel := range data.Relationships {
-				if rel.Type == "scanlation_group" && rel.ID == m.GroupID {
-					chapterNum64, err := strconv.ParseFloat(data.Attributes.Chapter, 32)
-					if err != nil {
-						return err
-					}
-					chapterNum := float32(chapterNum64)
-
-					var title string
-					if data.Attributes.Title != nil {
-						title = *data.Attributes.Title
-					}
-
-					manga.Chapters[chapterNum] = domain.Chapter{
-						ID:     data.ID,
-						Number: chapterNum,
-						Title:  sanitize.Filename(title),
-					}
-				}
+			// the language is preferred over the group
+			language := strings.ToLower(data.Attributes.TranslatedLanguage)
+			languageRank := slices.Index(m.languages, language)
+			if languageRank < 0 {
+				languageRank = len(m.languages)
 			}
-		}
 
-		if len(manga.Chapters) == 0 {
-			return fmt.Errorf("failed to get chapters for id: %s", m.MangaID)
+			ranks[data.ID] = languageRank*groupRanks + rank
+
+			manga.AddChapter(domain.Chapter{
+				ID:          data.ID,
+				Number:      chapterNum,
+				Volume:      volume,
+				Title:       sanitize.Filename(title),
+				PublishedAt: data.Attributes.PublishAt,
+				Group:       group,
+				Language:    data.Attributes.TranslatedLanguage,
+				Pages:       data.Attributes.Pages,
+			})
 		}
 
 		chapterCount += len(chapterResp.Data)
 
-		if chapterCount == chapterResp.Total {
-			return errFunc
+		if len(chapterResp.Data) == 0 || chapterCount >= chapterResp.Total {
+			break
 		}
 
 		offset += mangadexLimit
 	}
+
+	if len(manga.Chapters) == 0 {
+		return fmt.Errorf("failed to get chapters for id: %s", m.MangaID)
+	}
+
+	// only the releases in the language and of the group with the highest priority are kept for every chapter
+	for key, releases := range manga.Chapters {
+		best := slices.MinFunc(releases, func(a, b domain.Chapter) int {
+			return ranks[a.ID] - ranks[b.ID]
+		})
+
+		manga.Chapters[key] = slices.DeleteFunc(releases, func(release domain.Chapter) bool {
+			return ranks[release.ID] != ranks[best.ID]
+		})
+	}
+
+	return nil
 }
 
 func (m *mangadex) GetImageURLs(ctx context.Context, chapter *domain.Chapter) error {
@@ -248,22 +495,198 @@ func (m *mangadex) GetImageURLs(ctx context.Context, chapter *domain.Chapter) er
 		return err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
+	if err := m.get(ctx, mangadexAtHomeLimiter, path, &chapterResp); err != nil {
+		return err
+	}
+
+	images := chapterResp.Chapter.Data
+	if m.Quality == mangadexQualityDataSaver {
+		images = chapterResp.Chapter.DataSaver
+	}
+
+	for _, imageURL := range images {
+		imagePath, err := url.JoinPath(chapterResp.BaseURL, m.Quality, chapterResp.Chapter.Hash, imageURL)
+		if err != nil {
+			return err
+		}
+
+		imageInfo := domain.ImageInfo{ImageURL: imagePath}
+
+		// pages the at-home server fails to deliver are fetched from the main uploads server instead
+		if chapterResp.BaseURL != mangadexUploadsURL {
+			uploadsPath, err := url.JoinPath(mangadexUploadsURL, m.Quality, chapterResp.Chapter.Hash, imageURL)
+			if err != nil {
+				return err
+			}
+			imageInfo.FallbackURLs = []string{uploadsPath}
+		}
+
+		imageInfos = append(imageInfos, imageInfo)
+	}
+
+	if len(imageInfos) == 0 {
+		return fmt.Errorf("failed to get image urls for chapter id: %s", chapter.ID)
+	}
+
+	chapter.ImageInfo = imageInfos
+	chapter.Reporter = newMangadexReporter(m)
+
+	return nil
+}
+
+// mangadexReporter reports the images of a single chapter and keeps track of its reports that haven't been sent yet
+type mangadexReporter struct {
+	source *mangadex
+
+	mu      sync.Mutex
+	pending int
+	// idle is closed once no reports are pending
+	idle chan struct{}
+}
+
+func newMangadexReporter(source *mangadex) *mangadexReporter {
+	idle := make(chan struct{})
+	close(idle)
+
+	return &mangadexReporter{source: source, idle: idle}
+}
+
+// mangadexQueuedReport is an image report waiting to be sent by a report worker
+type mangadexQueuedReport struct {
+	ctx      context.Context
+	reporter *mangadexReporter
+	report   domain.ImageReport
+	deadline time.Time
+}
+
+// ReportImage reports how fetching an image from an at-home server went, which mangadex uses to keep track of the
+// health of the servers, images of the main uploads server aren't reported. Reports are queued and sent in the
+// background so a slow report server doesn't hold up downloads, reports still queued after mangadexReportDeadline
+// are given up
+func (r *mangadexReporter) ReportImage(ctx context.Context, report domain.ImageReport) {
+	if strings.HasPrefix(report.URL, mangadexUploadsURL) || ctx.Err() != nil {
+		return
+	}
+
+	mangadexReportsOnce.Do(func() {
+		for i := 0; i < mangadexReportWorkers; i++ {
+			go mangadexReportWorker()
+		}
+	})
+
+	r.add()
+
+	select {
+	case mangadexReports <- mangadexQueuedReport{ctx: ctx, reporter: r, report: report, deadline: time.Now().Add(mangadexReportDeadline)}:
+	case <-ctx.Done():
+		r.done()
+	}
+}
+
+// Flush waits until the reports of the chapter are sent or ctx is done
+func (r *mangadexReporter) Flush(ctx context.Context) {
+	r.mu.Lock()
+	idle := r.idle
+	r.mu.Unlock()
+
+	select {
+	case <-idle:
+	case <-ctx.Done():
+	}
+}
+
+// add marks a report as pending
+func (r *mangadexReporter) add() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.pending == 0 {
+		r.idle = make(chan struct{})
+	}
+	r.pending++
+}
+
+// done marks a pending report as sent or given up
+func (r *mangadexReporter) done() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.pending--
+	if r.pending == 0 {
+		close(r.idle)
+	}
+}
+
+// mangadexReportWorker sends the queued reports until the program exits
+func mangadexReportWorker() {
+	for queued := range mangadexReports {
+		if time.Now().Before(queued.deadline) {
+			queued.reporter.source.sendReport(queued.ctx, queued.report)
+		}
+		queued.reporter.done()
+	}
+}
+
+// sendReport posts the report to the report server, reports are best effort so errors are ignored
+func (m *mangadex) sendReport(ctx context.Context, report domain.ImageReport) {
+	body, err := json.Marshal(mangadexReport{
+		URL:      report.URL,
+		Success:  report.Success,
+		Bytes:    report.Bytes,
+		Duration: report.Duration.Milliseconds(),
+		Cached:   report.Cached,
+	})
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, mangadexReportTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, mangadexReportURL, bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+
+	req.Header.Set("User-Agent", "mangarr")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := m.Client.Do(req)
+	if err != nil {
+		return
+	}
+	resp.Body.Close()
+}
+
+// get requests the url from the api while respecting its rate limits and decodes the json response into v
+func (m *mangadex) get(ctx context.Context, limiter *sharedhttp.Limiter, u string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("User-Agent", "mangarr")
 
-	errFunc := retry.Do(func() error {
-		resp, err := sharedhttp.ExecRequest(*m.Client, req)
+	return retry.Do(func() error {
+		if err := limiter.Wait(ctx); err != nil {
+			return retry.Unrecoverable(err)
+		}
+
+		resp, err := m.Client.Do(req)
 		if err != nil {
 			return err
 		}
+		defer resp.Body.Close()
+
+		limiter.Update(resp)
+
+		if err := sharedhttp.CheckResponse(resp); err != nil {
+			return err
+		}
 
 		buf := bufio.NewReader(resp.Body)
 
-		err = json.NewDecoder(buf).Decode(&chapterResp)
+		err = json.NewDecoder(buf).Decode(v)
 		if err != nil {
 			return retry.Unrecoverable(err)
 		}
@@ -273,22 +696,126 @@ func (m *mangadex) GetImageURLs(ctx context.Context, chapter *domain.Chapter) er
 		retry.Delay(time.Second*3),
 		retry.Attempts(3),
 		retry.MaxJitter(time.Second*1),
+		retry.DelayType(sharedhttp.RetryAfterDelay),
 	)
+}
 
-	for _, imageURL := range chapterResp.Chapter.Data {
-		imagePath, err := url.JoinPath(chapterResp.BaseURL, "data", chapterResp.Chapter.Hash, imageURL)
-		if err != nil {
-			return err
+func (m *mangadex) Search(ctx context.Context, query string) ([]domain.SearchResult, error) {
+	var searchResp mangadexSearch
+
+	params := url.Values{
+		"title":                []string{query},
+		"limit":                []string{fmt.Sprintf("%d", mangadexSearchLimit)},
+		"includes[]":           []string{"cover_art"},
+		"order[relevance]":     []string{"desc"},
+		"contentRating[]":      []string{"safe", "suggestive", "erotica"},
+		"hasAvailableChapters": []string{"true"},
+	}
+
+	path, err := url.JoinPath(mangadexURL, "manga")
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(path)
+	if err != nil {
+		return nil, err
+	}
+
+	u.RawQuery = params.Encode()
+
+	if err := m.get(ctx, mangadexLimiter, u.String(), &searchResp); err != nil {
+		return nil, err
+	}
+
+	results := make([]domain.SearchResult, 0, len(searchResp.Data))
+
+	for _, data := range searchResp.Data {
+		result := domain.SearchResult{
+			Title:    data.Attributes.Title["en"],
+			ID:       data.ID,
+			URL:      mangadexSiteURL + "/title/" + data.ID,
+			Language: strings.Join(data.Attributes.AvailableTranslatedLanguages, ", "),
+		}
+
+		// fall back to any title if there is no english one
+		if len(result.Title) == 0 {
+			for _, title := range data.Attributes.Title {
+				result.Title = title
+				break
+			}
 		}
 
-		imageInfos = append(imageInfos, domain.ImageInfo{ImageURL: imagePath})
+		for _, rel := range data.Relationships {
+			if rel.Type == "cover_art" && len(rel.Attributes.FileName) != 0 {
+				result.CoverURL = mangadexUploadsURL + "/covers/" + data.ID + "/" + rel.Attributes.FileName
+			}
+		}
+
+		results = append(results, result)
 	}
 
-	if len(imageInfos) == 0 {
-		return fmt.Errorf("failed to get image urls for chapter id: %s", chapter.ID)
+	return results, nil
+}
+
+// parseMangadexGroups parses the group input of the source
+func parseMangadexGroups(input string) (mangadexGroups, error) {
+	var groups mangadexGroups
+
+	for _, value := range strings.Split(input, ",") {
+		value = strings.TrimSpace(value)
+
+		switch {
+		case len(value) == 0:
+			continue
+		case strings.EqualFold(value, mangadexAnyGroup):
+			groups.Any = true
+		case strings.HasPrefix(value, "!"):
+			id := strings.TrimSpace(strings.TrimPrefix(value, "!"))
+			if _, err := uuid.Parse(id); err != nil {
+				return mangadexGroups{}, fmt.Errorf("invalid mangadex group id to block %q: %w", id, err)
+			}
+			groups.Blocked = append(groups.Blocked, id)
+		default:
+			if _, err := uuid.Parse(value); err != nil {
+				return mangadexGroups{}, fmt.Errorf("invalid mangadex group id %q: %w", value, err)
+			}
+			groups.Priority = append(groups.Priority, value)
+		}
 	}
 
-	chapter.ImageInfo = imageInfos
+	// a blocklist on its own accepts every other group
+	if len(groups.Priority) == 0 && len(groups.Blocked) != 0 {
+		groups.Any = true
+	}
+
+	if len(groups.Priority) == 0 && !groups.Any {
+		return mangadexGroups{}, fmt.Errorf("mangadex group is required, use %q to accept every group", mangadexAnyGroup)
+	}
+
+	return groups, nil
+}
+
+// rank returns the priority and name of the highest priority group of a chapter, lower ranks are preferred, it
+// returns false if none of the groups of the chapter are accepted or one of them is blocked
+func (g mangadexGroups) rank(relationships []mangadexRelationship) (int, string, bool) {
+	rank, name, ok := len(g.Priority), "", g.Any
+
+	for _, rel := range relationships {
+		if rel.Type != "scanlation_group" {
+			continue
+		}
+
+		if slices.Contains(g.Blocked, rel.ID) {
+			return 0, "", false
+		}
+
+		if i := slices.Index(g.Priority, rel.ID); i >= 0 && i < rank {
+			rank, name, ok = i, rel.Attributes.Name, true
+		} else if g.Any && len(name) == 0 {
+			name = rel.Attributes.Name
+		}
+	}
 
-	return errFunc
+	return rank, name, ok
 }
@@ -0,0 +1,214 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"mangarr/internal/domain"
+)
+
+// blockingTransport holds every request until it is released
+type blockingTransport struct {
+	started atomic.Int32
+	release chan struct{}
+}
+
+func (b *blockingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	b.started.Add(1)
+
+	select {
+	case <-b.release:
+	case <-req.Context().Done():
+	}
+
+	return nil, errors.New("report server unavailable")
+}
+
+func TestMangadexReportImageDoesNotBlock(t *testing.T) {
+	transport := &blockingTransport{release: make(chan struct{})}
+	r := newMangadexReporter(&mangadex{Client: &http.Client{Transport: transport}})
+
+	reports := 3 * mangadexReportWorkers
+
+	start := time.Now()
+	for i := 0; i < reports; i++ {
+		r.ReportImage(context.Background(), domain.ImageReport{URL: "https://at-home.example.org/data/hash/1.png", Success: true})
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("reporting took %s while the report server hangs", elapsed)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for transport.started.Load() < mangadexReportWorkers && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if started := transport.started.Load(); started != mangadexReportWorkers {
+		t.Errorf("reports in flight = %d, want %d", started, mangadexReportWorkers)
+	}
+
+	close(transport.release)
+
+	// every report is sent once the report server answers again
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	r.Flush(ctx)
+
+	if ctx.Err() != nil {
+		t.Error("Flush returned before the reports were sent")
+	}
+	if started := transport.started.Load(); started != int32(reports) {
+		t.Errorf("reports sent = %d, want %d", started, reports)
+	}
+}
+
+func TestMangadexReporterFlushOnlyWaitsForItsChapter(t *testing.T) {
+	hanging := &blockingTransport{release: make(chan struct{})}
+	defer close(hanging.release)
+
+	answering := &blockingTransport{release: make(chan struct{})}
+	close(answering.release)
+
+	other := newMangadexReporter(&mangadex{Client: &http.Client{Transport: hanging}})
+	other.ReportImage(context.Background(), domain.ImageReport{URL: "https://at-home.example.org/data/hash/1.png"})
+
+	r := newMangadexReporter(&mangadex{Client: &http.Client{Transport: answering}})
+	r.ReportImage(context.Background(), domain.ImageReport{URL: "https://at-home.example.org/data/hash/2.png"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	r.Flush(ctx)
+
+	if ctx.Err() != nil {
+		t.Error("Flush waited for the reports of another chapter")
+	}
+}
+
+func TestMangadexReportImageSkipsUploads(t *testing.T) {
+	transport := &blockingTransport{release: make(chan struct{})}
+	close(transport.release)
+
+	r := newMangadexReporter(&mangadex{Client: &http.Client{Transport: transport}})
+	r.ReportImage(context.Background(), domain.ImageReport{URL: mangadexUploadsURL + "/data/hash/1.png"})
+
+	time.Sleep(50 * time.Millisecond)
+
+	if started := transport.started.Load(); started != 0 {
+		t.Errorf("images of the uploads server were reported %d times", started)
+	}
+}
+
+const (
+	mangadexTestGroupA = "0d7e9fbb-1c94-4d5e-9f2a-3a0c3f1e2b01"
+	mangadexTestGroupB = "5b2f6c3e-8a41-4e0f-b7d2-9c1e4a6f3b02"
+	mangadexTestGroupC = "a9c4e2d1-6f3b-4b8a-8e5c-1d2f3a4b5c03"
+)
+
+func TestParseMangadexGroups(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		priority []string
+		any      bool
+		blocked  []string
+		wantErr  bool
+	}{
+		{name: "single", input: mangadexTestGroupA, priority: []string{mangadexTestGroupA}},
+		{name: "priority order", input: mangadexTestGroupB + ", " + mangadexTestGroupA, priority: []string{mangadexTestGroupB, mangadexTestGroupA}},
+		{name: "any", input: "any", any: true},
+		{name: "priority and any", input: mangadexTestGroupA + ",ANY", priority: []string{mangadexTestGroupA}, any: true},
+		{name: "blocklist only accepts every other group", input: "!" + mangadexTestGroupC, any: true, blocked: []string{mangadexTestGroupC}},
+		{name: "priority and blocklist", input: mangadexTestGroupA + ",! " + mangadexTestGroupC, priority: []string{mangadexTestGroupA}, blocked: []string{mangadexTestGroupC}},
+		{name: "empty", input: " , ", wantErr: true},
+		{name: "invalid id", input: "Asura Scans", wantErr: true},
+		{name: "invalid blocked id", input: "any,!Asura Scans", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseMangadexGroups(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if !slices.Equal(got.Priority, tt.priority) {
+				t.Errorf("Priority = %v, want %v", got.Priority, tt.priority)
+			}
+			if got.Any != tt.any {
+				t.Errorf("Any = %v, want %v", got.Any, tt.any)
+			}
+			if !slices.Equal(got.Blocked, tt.blocked) {
+				t.Errorf("Blocked = %v, want %v", got.Blocked, tt.blocked)
+			}
+		})
+	}
+}
+
+func mangadexTestRelationships(ids ...string) []mangadexRelationship {
+	names := map[string]string{mangadexTestGroupA: "Group A", mangadexTestGroupB: "Group B", mangadexTestGroupC: "Group C"}
+
+	relationships := []mangadexRelationship{{ID: "f3a1b2c4-0000-4000-8000-000000000000", Type: "user"}}
+	for _, id := range ids {
+		rel := mangadexRelationship{ID: id, Type: "scanlation_group"}
+		rel.Attributes.Name = names[id]
+		relationships = append(relationships, rel)
+	}
+
+	return relationships
+}
+
+func TestMangadexGroupsRank(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		groups   []string
+		wantRank int
+		wantName string
+		wantOK   bool
+	}{
+		{name: "first priority", input: mangadexTestGroupA + "," + mangadexTestGroupB, groups: []string{mangadexTestGroupA}, wantRank: 0, wantName: "Group A", wantOK: true},
+		{name: "second priority", input: mangadexTestGroupA + "," + mangadexTestGroupB, groups: []string{mangadexTestGroupB}, wantRank: 1, wantName: "Group B", wantOK: true},
+		{name: "joint release ranks by best group", input: mangadexTestGroupA + "," + mangadexTestGroupB, groups: []string{mangadexTestGroupB, mangadexTestGroupA}, wantRank: 0, wantName: "Group A", wantOK: true},
+		{name: "other group rejected", input: mangadexTestGroupA, groups: []string{mangadexTestGroupC}, wantOK: false},
+		{name: "no group rejected", input: mangadexTestGroupA, wantOK: false},
+		{name: "any ranks other groups last", input: mangadexTestGroupA + ",any", groups: []string{mangadexTestGroupC}, wantRank: 1, wantName: "Group C", wantOK: true},
+		{name: "any accepts chapters without group", input: "any", wantRank: 0, wantOK: true},
+		{name: "blocklist only accepts other groups", input: "!" + mangadexTestGroupC, groups: []string{mangadexTestGroupA}, wantRank: 0, wantName: "Group A", wantOK: true},
+		{name: "blocklist only rejects blocked group", input: "!" + mangadexTestGroupC, groups: []string{mangadexTestGroupC}, wantOK: false},
+		{name: "blocked group wins over priority group", input: mangadexTestGroupA + ",!" + mangadexTestGroupC, groups: []string{mangadexTestGroupA, mangadexTestGroupC}, wantOK: false},
+		{name: "blocked group wins regardless of order", input: mangadexTestGroupA + ",!" + mangadexTestGroupC, groups: []string{mangadexTestGroupC, mangadexTestGroupA}, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groups, err := parseMangadexGroups(tt.input)
+			if err != nil {
+				t.Fatalf("parseMangadexGroups: %v", err)
+			}
+
+			rank, name, ok := groups.rank(mangadexTestRelationships(tt.groups...))
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+
+			if rank != tt.wantRank {
+				t.Errorf("rank = %d, want %d", rank, tt.wantRank)
+			}
+			if name != tt.wantName {
+				t.Errorf("name = %q, want %q", name, tt.wantName)
+			}
+		})
+	}
+}
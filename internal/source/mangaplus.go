@@ -2,13 +2,14 @@ package source
 
 import (
 	"bufio"
+	"cmp"
 	"context"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"regexp"
-	"strconv"
+	"slices"
 	"strings"
 	"time"
 
@@ -21,24 +22,70 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
-const mangaplusURL = "https://jumpg-webapi.tokyo-cdn.com/api"
+const (
+	mangaplusURL     = "https://jumpg-webapi.tokyo-cdn.com/api"
+	mangaplusSiteURL = "https://mangaplus.shueisha.co.jp"
+)
+
+// mangaplus options
+const (
+	mangaplusOptionLanguage = "language"
+	mangaplusOptionQuality  = "quality"
+)
+
+var mangaplusQualities = []string{"low", "high", "super_high"}
 
 var mangaplusID = regexp.MustCompile(`^[1-9][0-9][0-9][0-9][0-9][0-9]$`)
 
+// mangaplusLanguages maps the language of a title to its language code
+var mangaplusLanguages = map[int32]string{
+	0: "en",
+	1: "es",
+	2: "fr",
+	3: "id",
+	4: "pt-br",
+	5: "ru",
+	6: "th",
+	7: "de",
+	9: "vi",
+}
+
 type mangaplus struct {
-	MangaID string
-	Client  *http.Client
+	MangaID  string
+	Language string
+	Quality  string
+	Client   *http.Client
+}
+
+func init() {
+	Register(Definition{
+		Key:  "mangaplus",
+		Name: "MANGA Plus",
+		Fields: []Field{
+			{Name: InputManga, Required: true, Help: "Six digit title ID or URL of the manga on MANGA Plus", Example: "https://mangaplus.shueisha.co.jp/titles/100274"},
+		},
+		Options: []Option{
+			{Name: mangaplusOptionLanguage, Help: "Language of the edition to download, defaults to the edition of the title", Example: "es"},
+			{Name: mangaplusOptionQuality, Help: `Quality of the images, one of "low", "high" and "super_high", defaults to "super_high"`, Example: "high"},
+		},
+		Languages: []string{"en", "es", "fr", "id", "pt-br", "ru", "th", "de", "vi"},
+		New: func(in Input) domain.Source {
+			return NewMangaPlus(in.Manga, in.Option(mangaplusOptionLanguage), in.Option(mangaplusOptionQuality))
+		},
+	})
 }
 
-func NewMangaPlus(mangaID string) domain.Source {
+func NewMangaPlus(mangaID, language, quality string) domain.Source {
 	client := &http.Client{
 		Timeout:   60 * time.Second,
 		Transport: sharedhttp.Transport,
 	}
 
 	return &mangaplus{
-		MangaID: mangaID,
-		Client:  client,
+		MangaID:  mangaID,
+		Language: language,
+		Quality:  quality,
+		Client:   client,
 	}
 }
 
@@ -51,53 +98,99 @@ func (m *mangaplus) ValidateInput() error {
 		return fmt.Errorf("mangaplus manga id is required")
 	}
 
+	// urls of a title are reduced to its id
+	if strings.Contains(m.MangaID, "/") {
+		u, err := url.Parse(m.MangaID)
+		if err != nil {
+			return err
+		}
+
+		if host := u.Hostname(); host != "mangaplus.shueisha.co.jp" && !strings.HasSuffix(host, ".mangaplus.shueisha.co.jp") {
+			return fmt.Errorf("the url for mangaplus must be on mangaplus.shueisha.co.jp")
+		}
+
+		segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+		if len(segments) != 2 || segments[0] != "titles" {
+			return fmt.Errorf("the url for mangaplus must point to a title, e.g. %s/titles/100274", mangaplusSiteURL)
+		}
+
+		m.MangaID = segments[1]
+	}
+
 	if !mangaplusID.MatchString(m.MangaID) {
 		return fmt.Errorf("invalid mangaplus id")
 	}
 
-	return nil
-}
-
-func (m *mangaplus) GetManga(ctx context.Context) (domain.Manga, error) {
-	params := url.Values{
-		"title_id": []string{m.MangaID},
+	if len(m.Language) != 0 && len(mangaplusLanguage(m.Language)) == 0 {
+		return fmt.Errorf("unsupported mangaplus language %q", m.Language)
 	}
 
-	path, err := url.JoinPath(mangaplusURL, "title_detailV3")
-	if err != nil {
-		return domain.Manga{}, err
+	m.Quality = strings.ToLower(strings.TrimSpace(m.Quality))
+	if len(m.Quality) == 0 {
+		m.Quality = "super_high"
 	}
 
-	u, err := url.Parse(path)
-	if err != nil {
-		return domain.Manga{}, err
+	if !slices.Contains(mangaplusQualities, m.Quality) {
+		return fmt.Errorf("invalid mangaplus quality %q, must be one of: %s", m.Quality, strings.Join(mangaplusQualities, ", "))
 	}
 
-	u.RawQuery = params.Encode()
+	return nil
+}
 
-	protoResp, err := m.getProtoResponse(ctx, u.String())
+func (m *mangaplus) GetManga(ctx context.Context) (domain.Manga, error) {
+	titleDetail, err := m.getTitleDetail(ctx)
 	if err != nil {
 		return domain.Manga{}, err
 	}
 
-	chaptersGroup := protoResp.GetSuccess().GetTitleDetailView().GetChapterListGroup()
-
-	c := make(map[float32]domain.Chapter)
+	// the editions of a title in other languages have their own title ids
+	if len(m.Language) != 0 && mangaplusLanguages[titleDetail.GetTitle().GetLanguage()] != mangaplusLanguage(m.Language) {
+		titleID, err := m.getEditionID(ctx)
+		if err != nil {
+			return domain.Manga{}, err
+		}
+		m.MangaID = titleID
 
-	for _, chapters := range chaptersGroup {
-		err := m.addChapters(c, chapters.GetFirstChapterList(), chapters.GetLastChapterList())
+		titleDetail, err = m.getTitleDetail(ctx)
 		if err != nil {
 			return domain.Manga{}, err
 		}
 	}
+	chaptersGroup := titleDetail.GetChapterListGroup()
+
+	c := make(map[string][]domain.Chapter)
+
+	language := mangaplusLanguages[titleDetail.GetTitle().GetLanguage()]
+
+	// older titles only list their chapters outside of chapter groups
+	chapterLists := [][]*protobuf.Chapter{titleDetail.GetFirstChapterList(), titleDetail.GetLastChapterList()}
+	for _, chapters := range chaptersGroup {
+		chapterLists = append(chapterLists, chapters.GetFirstChapterList(), chapters.GetMidChapterList(), chapters.GetLastChapterList())
+	}
 
-	title := protoResp.GetSuccess().GetTitleDetailView().GetTitle().GetName()
+	m.addChapters(c, language, chapterLists...)
+
+	title := titleDetail.GetTitle().GetName()
 	if len(title) == 0 {
 		return domain.Manga{}, fmt.Errorf("failed to get manga for id: %s", m.MangaID)
 	}
 
+	var authors []string
+	for _, author := range strings.Split(titleDetail.GetTitle().GetAuthor(), "/") {
+		if author = strings.TrimSpace(author); len(author) != 0 {
+			authors = append(authors, author)
+		}
+	}
+
 	return domain.Manga{
-		Title:    sanitize.Filename(title),
+		Title: sanitize.Filename(title),
+		Metadata: domain.Metadata{
+			Authors:          authors,
+			Synopsis:         titleDetail.GetOverview(),
+			CoverURL:         titleDetail.GetTitle().GetPortraitImageUrl(),
+			ReadingDirection: domain.DirectionRightToLeft,
+			SourceURL:        mangaplusSiteURL + "/titles/" + m.MangaID,
+		},
 		Chapters: c,
 	}, nil
 }
@@ -107,10 +200,14 @@ func (m *mangaplus) GetChapters(_ context.Context, _ domain.Manga) error {
 }
 
 func (m *mangaplus) GetImageURLs(ctx context.Context, chapter *domain.Chapter) error {
+	if err := chapter.CheckAvailable(time.Now()); err != nil {
+		return err
+	}
+
 	params := url.Values{
 		"chapter_id":  []string{chapter.ID},
 		"split":       []string{"yes"},
-		"img_quality": []string{"super_high"},
+		"img_quality": []string{m.Quality},
 	}
 
 	path, err := url.JoinPath(mangaplusURL, "manga_viewer")
@@ -130,9 +227,15 @@ func (m *mangaplus) GetImageURLs(ctx context.Context, chapter *domain.Chapter) e
 		return err
 	}
 
+	// the viewer is left out for chapters that have expired in the meantime
+	viewer := protoResp.GetSuccess().GetMangaViewer()
+	if viewer == nil {
+		return fmt.Errorf("chapter %s is no longer available: %w", chapter.Number, domain.ErrChapterUnavailable)
+	}
+
 	var imageInfos []domain.ImageInfo
 
-	for _, page := range protoResp.GetSuccess().GetMangaViewer().GetPages() {
+	for _, page := range viewer.GetPages() {
 		if page.GetMangaPage() != nil {
 			imageInfos = append(imageInfos, domain.ImageInfo{
 				ImageURL:      page.GetMangaPage().GetImageUrl(),
@@ -149,6 +252,106 @@ func (m *mangaplus) GetImageURLs(ctx context.Context, chapter *domain.Chapter) e
 	return nil
 }
 
+func (m *mangaplus) Search(ctx context.Context, query string) ([]domain.SearchResult, error) {
+	path, err := url.JoinPath(mangaplusURL, "title_list", "allV2")
+	if err != nil {
+		return nil, err
+	}
+
+	protoResp, err := m.getProtoResponse(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	query = strings.ToLower(query)
+
+	var results []domain.SearchResult
+
+	for _, group := range protoResp.GetSuccess().GetAllTitlesViewV2().GetAllTitlesGroup() {
+		groupMatches := strings.Contains(strings.ToLower(group.GetTheTitle()), query)
+
+		for _, title := range group.GetTitles() {
+			if !groupMatches && !strings.Contains(strings.ToLower(title.GetName()), query) {
+				continue
+			}
+
+			titleID := fmt.Sprintf("%d", title.GetTitleId())
+
+			results = append(results, domain.SearchResult{
+				Title:    title.GetName(),
+				ID:       titleID,
+				URL:      mangaplusSiteURL + "/titles/" + titleID,
+				CoverURL: title.GetPortraitImageUrl(),
+				Language: mangaplusLanguages[title.GetLanguage()],
+			})
+		}
+	}
+
+	return results, nil
+}
+
+// getTitleDetail requests the details of the title
+func (m *mangaplus) getTitleDetail(ctx context.Context) (*protobuf.TitleDetailView, error) {
+	params := url.Values{
+		"title_id": []string{m.MangaID},
+	}
+
+	path, err := url.JoinPath(mangaplusURL, "title_detailV3")
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(path)
+	if err != nil {
+		return nil, err
+	}
+
+	u.RawQuery = params.Encode()
+
+	protoResp, err := m.getProtoResponse(ctx, u.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return protoResp.GetSuccess().GetTitleDetailView(), nil
+}
+
+// getEditionID returns the title id of the edition of the title in the configured language, the editions of a title
+// are grouped together in the list of all titles
+func (m *mangaplus) getEditionID(ctx context.Context) (string, error) {
+	path, err := url.JoinPath(mangaplusURL, "title_list", "allV2")
+	if err != nil {
+		return "", err
+	}
+
+	protoResp, err := m.getProtoResponse(ctx, path)
+	if err != nil {
+		return "", err
+	}
+
+	language := mangaplusLanguage(m.Language)
+
+	for _, group := range protoResp.GetSuccess().GetAllTitlesViewV2().GetAllTitlesGroup() {
+		if !slices.ContainsFunc(group.GetTitles(), func(title *protobuf.Title) bool {
+			return fmt.Sprintf("%d", title.GetTitleId()) == m.MangaID
+		}) {
+			continue
+		}
+
+		var available []string
+		for _, title := range group.GetTitles() {
+			if mangaplusLanguages[title.GetLanguage()] == language {
+				return fmt.Sprintf("%d", title.GetTitleId()), nil
+			}
+			available = append(available, mangaplusLanguages[title.GetLanguage()])
+		}
+
+		return "", fmt.Errorf("title %s has no %s edition, available languages: %s", m.MangaID, language, strings.Join(available, ", "))
+	}
+
+	return "", fmt.Errorf("failed to find the editions of title: %s", m.MangaID)
+}
+
 func (m *mangaplus) getProtoResponse(ctx context.Context, path string) (*protobuf.Response, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, path, nil)
 	if err != nil {
@@ -184,22 +387,79 @@ func (m *mangaplus) getProtoResponse(ctx context.Context, path string) (*protobu
 	return &protoResp, retryErr
 }
 
-func (m *mangaplus) addChapters(chapters map[float32]domain.Chapter, chapterLists ...[]*protobuf.Chapter) error {
-	for _, chapterList := range chapterLists {
-		for _, chapter := range chapterList {
-			name := strings.Trim(chapter.GetName(), "#")
-			number, err := strconv.ParseFloat(name, 32)
-			if err != nil {
-				return err
-			}
+// addChapters adds the chapters of the lists to chapters, chapters listed more than once are only added the first time.
+// Extras and oneshots often share a name like "ex", so their subtitle is added to their label, e.g. "ex Special 2",
+// which keeps their labels the same when other extras expire. Numbered chapters with the same number are kept as
+// releases of that number
+func (m *mangaplus) addChapters(chapters map[string][]domain.Chapter, language string, chapterLists ...[]*protobuf.Chapter) {
+	seen := make(map[string]bool)
+	for _, releases := range chapters {
+		for _, release := range releases {
+			seen[release.ID] = true
+		}
+	}
+
+	// extras sharing both name and subtitle are told apart by their id, the oldest one keeps the plain label
+	all := slices.Concat(chapterLists...)
+	slices.SortStableFunc(all, func(a, b *protobuf.Chapter) int {
+		return cmp.Compare(a.GetChapterId(), b.GetChapterId())
+	})
+
+	for _, chapter := range all {
+		id := fmt.Sprintf("%d", chapter.GetChapterId())
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		label := strings.Trim(chapter.GetName(), "#")
+		number := domain.ParseChapterNumber(label)
 
-			chapters[float32(number)] = domain.Chapter{
-				ID:     fmt.Sprintf("%d", chapter.GetChapterId()),
-				Number: float32(number),
-				Title:  chapter.GetSubTitle(),
+		if !number.IsNumeric() {
+			if subTitle := strings.TrimSpace(chapter.GetSubTitle()); len(subTitle) != 0 {
+				number = domain.ParseChapterNumber(label + " " + subTitle)
 			}
+			if len(chapters[number.Key()]) != 0 {
+				number = domain.ParseChapterNumber(number.Label + " " + id)
+			}
+		}
+
+		var publishedAt, availableUntil time.Time
+		if chapter.GetStartTimestamp() != 0 {
+			publishedAt = time.Unix(int64(chapter.GetStartTimestamp()), 0)
+		}
+		if chapter.GetEndTimestamp() != 0 {
+			availableUntil = time.Unix(int64(chapter.GetEndTimestamp()), 0)
+		}
+
+		chapters[number.Key()] = append(chapters[number.Key()], domain.Chapter{
+			ID:             id,
+			Number:         number,
+			Title:          chapter.GetSubTitle(),
+			PublishedAt:    publishedAt,
+			Language:       language,
+			AvailableFrom:  publishedAt,
+			AvailableUntil: availableUntil,
+		})
+	}
+}
+
+// mangaplusLanguage returns the language code of MangaPlus matching the given code, e.g. "pt-br" for "pt", or an
+// empty string if the language isn't supported
+func mangaplusLanguage(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+
+	for _, language := range mangaplusLanguages {
+		if language == code {
+			return language
 		}
 	}
 
-	return nil
+	for _, language := range mangaplusLanguages {
+		if prefix, _, _ := strings.Cut(language, "-"); prefix == code {
+			return language
+		}
+	}
+
+	return ""
 }
@@ -0,0 +1,102 @@
+package source
+
+import (
+	"slices"
+	"testing"
+
+	"mangarr/internal/domain"
+	"mangarr/internal/protobuf"
+)
+
+func TestMangaplusAddChapters(t *testing.T) {
+	first := []*protobuf.Chapter{
+		{ChapterId: 1001, Name: "#001", SubTitle: "Romance Dawn", StartTimestamp: 1700000000},
+		{ChapterId: 1002, Name: "#002"},
+		{ChapterId: 1090, Name: "#ex", SubTitle: "Special 1"},
+		{ChapterId: 1092, Name: "#ex", SubTitle: "Special 3"},
+	}
+	last := []*protobuf.Chapter{
+		// listed again in a later list
+		{ChapterId: 1002, Name: "#002"},
+		{ChapterId: 1091, Name: "#ex", SubTitle: "Special 2"},
+		{ChapterId: 1100, Name: "#100", EndTimestamp: 1800000000},
+		// a second release of a numbered chapter
+		{ChapterId: 1101, Name: "#100"},
+	}
+
+	m := &mangaplus{}
+	chapters := make(map[string][]domain.Chapter)
+	m.addChapters(chapters, "en", first, last)
+	// chapters of the title are listed once more in its chapter groups
+	m.addChapters(chapters, "en", last)
+
+	// extras keep their labels when an earlier extra has expired
+	expired := make(map[string][]domain.Chapter)
+	m.addChapters(expired, "en", last)
+	if releases := expired["ex special 2"]; len(releases) != 1 || releases[0].ID != "1091" {
+		t.Errorf("releases of extra without the expired one = %+v", releases)
+	}
+
+	var ids []string
+	for _, releases := range chapters {
+		for _, release := range releases {
+			ids = append(ids, release.ID)
+		}
+	}
+	slices.Sort(ids)
+
+	want := []string{"1001", "1002", "1090", "1091", "1092", "1100", "1101"}
+	if !slices.Equal(ids, want) {
+		t.Errorf("chapter ids = %v, want %v", ids, want)
+	}
+
+	if releases := chapters["2"]; len(releases) != 1 {
+		t.Errorf("releases of chapter 2 = %d, want 1", len(releases))
+	}
+	if releases := chapters["100"]; len(releases) != 2 {
+		t.Errorf("releases of chapter 100 = %d, want 2", len(releases))
+	}
+
+	extras := map[string]string{"ex special 1": "Special 1", "ex special 2": "Special 2", "ex special 3": "Special 3"}
+	for key, title := range extras {
+		releases := chapters[key]
+		if len(releases) != 1 || releases[0].Title != title {
+			t.Errorf("releases of %q = %+v, want one titled %q", key, releases, title)
+		}
+	}
+
+	romanceDawn := chapters["1"][0]
+	if romanceDawn.AvailableFrom.Unix() != 1700000000 || !romanceDawn.AvailableUntil.IsZero() {
+		t.Errorf("availability of chapter 1 = %s - %s", romanceDawn.AvailableFrom, romanceDawn.AvailableUntil)
+	}
+	if chapters["100"][0].AvailableUntil.Unix() != 1800000000 {
+		t.Errorf("chapter 100 available until %s", chapters["100"][0].AvailableUntil)
+	}
+}
+
+func TestMangaplusValidateInput(t *testing.T) {
+	tests := []struct {
+		input   string
+		id      string
+		wantErr bool
+	}{
+		{input: "100037", id: "100037"},
+		{input: "https://mangaplus.shueisha.co.jp/titles/100037", id: "100037"},
+		{input: "https://evilmangaplus.shueisha.co.jp/titles/100037", wantErr: true},
+		{input: "https://mangaplus.shueisha.co.jp.evil.org/titles/100037", wantErr: true},
+		{input: "https://mangaplus.shueisha.co.jp/viewer/1000486", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			m := &mangaplus{MangaID: tt.input, Quality: "high"}
+			err := m.ValidateInput()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateInput() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && m.MangaID != tt.id {
+				t.Errorf("MangaID = %q, want %q", m.MangaID, tt.id)
+			}
+		})
+	}
+}
@@ -0,0 +1,640 @@
+package source
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"mangarr/internal/domain"
+	"mangarr/internal/sanitize"
+	"mangarr/internal/sharedhttp"
+
+	"github.com/avast/retry-go"
+)
+
+// opds options
+const (
+	opdsOptionTitle        = "title"
+	opdsOptionTitlePattern = "titlePattern"
+	opdsOptionUsername     = "username"
+	opdsOptionPassword     = "password"
+)
+
+const (
+	opdsRelAcquisition = "http://opds-spec.org/acquisition"
+	opdsRelStream      = "http://vaemendis.net/opds-pse/stream"
+	opdsTypeDivina     = "application/divina+json"
+	// opdsMaxPages guards against following next links forever
+	opdsMaxPages = 100
+)
+
+var (
+	opdsVolumePattern = regexp.MustCompile(`(?i)\b(?:volume|vol\.?|v)\s*(\d+)`)
+	opdsNumberPattern = regexp.MustCompile(`(\d+(?:\.\d+)?)\D*$`)
+)
+
+// opdsFeed is an OPDS 1.2 atom feed
+type opdsFeed struct {
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle"`
+	Links    []opdsLink  `xml:"link"`
+	Entries  []opdsEntry `xml:"entry"`
+}
+
+type opdsEntry struct {
+	ID        string `xml:"id"`
+	Title     string `xml:"title"`
+	Updated   string `xml:"updated"`
+	Published string `xml:"published"`
+	Authors   []struct {
+		Name string `xml:"name"`
+	} `xml:"author"`
+	Links []opdsLink `xml:"link"`
+}
+
+type opdsLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+	// Count is the number of pages of a page streaming link
+	Count int `xml:"http://vaemendis.net/opds-pse/ns count,attr"`
+}
+
+// opds2Feed is an OPDS 2.0 json feed
+type opds2Feed struct {
+	Metadata struct {
+		Title string `json:"title"`
+	} `json:"metadata"`
+	Links        []opds2Link `json:"links"`
+	Publications []struct {
+		Metadata struct {
+			Identifier string          `json:"identifier"`
+			Title      string          `json:"title"`
+			Modified   string          `json:"modified"`
+			Published  string          `json:"published"`
+			Author     json.RawMessage `json:"author"`
+		} `json:"metadata"`
+		Links []opds2Link `json:"links"`
+	} `json:"publications"`
+}
+
+type opds2Link struct {
+	Href   string   `json:"href"`
+	Rel    opds2Rel `json:"rel"`
+	Type   string   `json:"type"`
+	Width  float64  `json:"width"`
+	Height float64  `json:"height"`
+}
+
+// opds2Rel holds the relations of a link, which may be a single string or a list
+type opds2Rel []string
+
+func (r *opds2Rel) UnmarshalJSON(data []byte) error {
+	var rel string
+	if err := json.Unmarshal(data, &rel); err == nil {
+		*r = opds2Rel{rel}
+		return nil
+	}
+
+	var rels []string
+	if err := json.Unmarshal(data, &rels); err != nil {
+		return err
+	}
+
+	*r = rels
+	return nil
+}
+
+func (r opds2Rel) has(rel string) bool {
+	for _, value := range r {
+		if value == rel || strings.HasPrefix(value, rel+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// opdsDivina is the manifest of a publication whose reading order lists its pages
+type opdsDivina struct {
+	ReadingOrder []opds2Link `json:"readingOrder"`
+}
+
+// opdsPublication is a publication of either feed format
+type opdsPublication struct {
+	ID        string
+	Title     string
+	Published time.Time
+	Authors   []string
+	// ArchiveURL links to a CBZ or ZIP archive of the publication
+	ArchiveURL string
+	// PagesURL is either a page streaming template or the url of a divina manifest
+	PagesURL string
+	Pages    int
+}
+
+type opds struct {
+	Client   *http.Client
+	FeedURL  string
+	Title    string
+	Group    string
+	Language string
+	Pattern  string
+	Username string
+	Password string
+
+	titlePattern *regexp.Regexp
+}
+
+func init() {
+	Register(Definition{
+		Key:  "opds",
+		Name: "OPDS catalog",
+		Fields: []Field{
+			{Name: InputManga, Required: true, Help: "URL of the OPDS 1.2 or 2.0 feed listing the books of the series", Example: "http://komga:25600/opds/v1.2/series/0B7VVBRD9SJ5E"},
+			{Name: InputGroup, Help: "Name used as group of the chapters, defaults to the host of the catalog"},
+			{Name: InputLanguage, Help: "Language of the chapters"},
+		},
+		Options: []Option{
+			{Name: opdsOptionTitle, Help: "Title of the manga, defaults to the title of the feed"},
+			{Name: opdsOptionTitlePattern, Help: "Regex matching the titles of the books, its first capture group is the chapter number, defaults to the last number in the title", Example: `Chapter (\d+)`},
+			{Name: opdsOptionUsername, Help: "Username for basic auth"},
+			{Name: opdsOptionPassword, Help: "Password for basic auth"},
+		},
+		Auth: "optional basic auth with the username and password options",
+		New: func(in Input) domain.Source {
+			return NewOPDS(in)
+		},
+	})
+}
+
+func NewOPDS(in Input) domain.Source {
+	client := &http.Client{
+		Timeout:   60 * time.Second,
+		Transport: sharedhttp.Transport,
+	}
+
+	return &opds{
+		Client:   client,
+		FeedURL:  in.Manga,
+		Title:    in.Option(opdsOptionTitle),
+		Group:    in.Group,
+		Language: in.Language,
+		Pattern:  in.Option(opdsOptionTitlePattern),
+		Username: in.Option(opdsOptionUsername),
+		Password: in.Option(opdsOptionPassword),
+	}
+}
+
+func (o *opds) String() string {
+	return "OPDS"
+}
+
+func (o *opds) ValidateInput() error {
+	u, err := url.Parse(o.FeedURL)
+	if err != nil {
+		return err
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("the feed url must be a http or https url")
+	}
+
+	// credentials in the url are used for basic auth as well
+	if u.User != nil && len(o.Username) == 0 {
+		o.Username = u.User.Username()
+		o.Password, _ = u.User.Password()
+		u.User = nil
+		o.FeedURL = u.String()
+	}
+
+	if len(o.Group) == 0 {
+		o.Group = u.Hostname()
+	}
+
+	if len(o.Pattern) != 0 {
+		pattern, err := regexp.Compile(o.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid title pattern: %w", err)
+		}
+		o.titlePattern = pattern
+	}
+
+	return nil
+}
+
+func (o *opds) GetManga(ctx context.Context) (domain.Manga, error) {
+	title, publications, err := o.getPublications(ctx)
+	if err != nil {
+		return domain.Manga{}, err
+	}
+
+	if len(o.Title) != 0 {
+		title = o.Title
+	}
+
+	if len(title) == 0 {
+		return domain.Manga{}, fmt.Errorf("failed to get manga for provided url: %s", o.FeedURL)
+	}
+
+	manga := domain.Manga{
+		URL:   o.FeedURL,
+		Title: sanitize.Filename(title),
+		Metadata: domain.Metadata{
+			SourceURL: o.FeedURL,
+		},
+		Chapters: make(map[string][]domain.Chapter),
+	}
+
+	for _, publication := range publications {
+		if len(publication.ArchiveURL) == 0 && len(publication.PagesURL) == 0 {
+			continue
+		}
+
+		label := o.chapterLabel(publication.Title)
+		if len(label) == 0 {
+			continue
+		}
+
+		for _, author := range publication.Authors {
+			if !slices.Contains(manga.Metadata.Authors, author) {
+				manga.Metadata.Authors = append(manga.Metadata.Authors, author)
+			}
+		}
+
+		manga.AddChapter(domain.Chapter{
+			ID:          publication.ID,
+			URL:         publication.PagesURL,
+			ArchiveURL:  publication.ArchiveURL,
+			Number:      domain.ParseChapterNumber(label),
+			Volume:      matchFirstGroup(opdsVolumePattern, publication.Title),
+			PublishedAt: publication.Published,
+			Group:       o.Group,
+			Language:    o.Language,
+			Pages:       publication.Pages,
+			Headers:     o.headers(publication.ArchiveURL),
+		})
+	}
+
+	if len(manga.Chapters) == 0 {
+		return domain.Manga{}, fmt.Errorf("failed to get chapters for manga: %s", manga.Title)
+	}
+
+	return manga, nil
+}
+
+func (o *opds) GetChapters(_ context.Context, _ domain.Manga) error {
+	return nil
+}
+
+func (o *opds) GetImageURLs(ctx context.Context, chapter *domain.Chapter) error {
+	// the archive is downloaded as is
+	if len(chapter.ArchiveURL) != 0 {
+		return nil
+	}
+
+	var imageInfos []domain.ImageInfo
+
+	if strings.Contains(chapter.URL, "{pageNumber}") {
+		template := opdsStripMaxWidth(chapter.URL)
+
+		// page numbers of the page streaming extension start at zero
+		for page := 0; page < chapter.Pages; page++ {
+			imageInfos = append(imageInfos, domain.ImageInfo{ImageURL: strings.ReplaceAll(template, "{pageNumber}", strconv.Itoa(page))})
+		}
+	} else {
+		body, _, err := o.get(ctx, chapter.URL)
+		if err != nil {
+			return err
+		}
+
+		var manifest opdsDivina
+		if err := json.Unmarshal(body, &manifest); err != nil {
+			return fmt.Errorf("failed to parse manifest: %w", err)
+		}
+
+		for _, link := range manifest.ReadingOrder {
+			imageInfos = append(imageInfos, domain.ImageInfo{
+				ImageURL: resolveURL(chapter.URL, link.Href),
+				Width:    link.Width,
+				Height:   link.Height,
+			})
+		}
+	}
+
+	if len(imageInfos) == 0 {
+		return fmt.Errorf("failed to get image urls for chapter number: %s", chapter.Number)
+	}
+
+	imageURLs := make([]string, 0, len(imageInfos))
+	for _, imageInfo := range imageInfos {
+		imageURLs = append(imageURLs, imageInfo.ImageURL)
+	}
+
+	chapter.ImageInfo = imageInfos
+	chapter.Headers = o.headers(imageURLs...)
+	return nil
+}
+
+// getPublications walks the pages of the feed and returns its title and publications
+func (o *opds) getPublications(ctx context.Context) (string, []opdsPublication, error) {
+	var title string
+	var publications []opdsPublication
+
+	visited := make(map[string]bool)
+
+	for pageURL, page := o.FeedURL, 0; len(pageURL) != 0 && !visited[pageURL] && page < opdsMaxPages; page++ {
+		visited[pageURL] = true
+
+		body, contentType, err := o.get(ctx, pageURL)
+		if err != nil {
+			return "", nil, err
+		}
+
+		var pageTitle, next string
+		var pagePublications []opdsPublication
+
+		if strings.Contains(contentType, "json") || bytes.HasPrefix(bytes.TrimSpace(body), []byte("{")) {
+			pageTitle, next, pagePublications, err = parseOPDS2(pageURL, body)
+		} else {
+			pageTitle, next, pagePublications, err = parseOPDS1(pageURL, body)
+		}
+		if err != nil {
+			return "", nil, err
+		}
+
+		if len(title) == 0 {
+			title = pageTitle
+		}
+
+		publications = append(publications, pagePublications...)
+		pageURL = next
+	}
+
+	return title, publications, nil
+}
+
+// parseOPDS1 returns the title, the next page and the publications of an OPDS 1.2 feed page
+func parseOPDS1(pageURL string, body []byte) (string, string, []opdsPublication, error) {
+	var feed opdsFeed
+	if err := xml.Unmarshal(body, &feed); err != nil {
+		return "", "", nil, fmt.Errorf("failed to parse feed: %w", err)
+	}
+
+	var next string
+	for _, link := range feed.Links {
+		if link.Rel == "next" {
+			next = resolveURL(pageURL, link.Href)
+		}
+	}
+
+	var publications []opdsPublication
+	for _, entry := range feed.Entries {
+		publication := opdsPublication{
+			ID:        strings.TrimSpace(entry.ID),
+			Title:     strings.TrimSpace(entry.Title),
+			Published: parseReleaseDate(entry.Published, time.RFC3339),
+		}
+
+		if publication.Published.IsZero() {
+			publication.Published = parseReleaseDate(entry.Updated, time.RFC3339)
+		}
+
+		for _, author := range entry.Authors {
+			publication.Authors = appendValue(publication.Authors, author.Name)
+		}
+
+		for _, link := range entry.Links {
+			href := resolveURL(pageURL, link.Href)
+
+			switch {
+			case strings.HasPrefix(link.Rel, opdsRelAcquisition) && isArchive(href, link.Type):
+				publication.ArchiveURL = href
+			case link.Rel == opdsRelStream && link.Count > 0:
+				publication.PagesURL = href
+				publication.Pages = link.Count
+			}
+		}
+
+		publications = append(publications, publication)
+	}
+
+	return strings.TrimSpace(feed.Title), next, publications, nil
+}
+
+// parseOPDS2 returns the title, the next page and the publications of an OPDS 2.0 feed page
+func parseOPDS2(pageURL string, body []byte) (string, string, []opdsPublication, error) {
+	var feed opds2Feed
+	if err := json.Unmarshal(body, &feed); err != nil {
+		return "", "", nil, fmt.Errorf("failed to parse feed: %w", err)
+	}
+
+	var next string
+	for _, link := range feed.Links {
+		if link.Rel.has("next") {
+			next = resolveURL(pageURL, link.Href)
+		}
+	}
+
+	var publications []opdsPublication
+	for _, data := range feed.Publications {
+		publication := opdsPublication{
+			ID:        data.Metadata.Identifier,
+			Title:     strings.TrimSpace(data.Metadata.Title),
+			Published: parseReleaseDate(data.Metadata.Published, time.RFC3339, time.DateOnly),
+			Authors:   opds2Names(data.Metadata.Author),
+		}
+
+		if publication.Published.IsZero() {
+			publication.Published = parseReleaseDate(data.Metadata.Modified, time.RFC3339)
+		}
+
+		for _, link := range data.Links {
+			href := resolveURL(pageURL, link.Href)
+
+			switch {
+			case link.Rel.has(opdsRelAcquisition) && isArchive(href, link.Type):
+				publication.ArchiveURL = href
+			case link.Type == opdsTypeDivina:
+				publication.PagesURL = href
+			}
+		}
+
+		if len(publication.ID) == 0 {
+			publication.ID = publication.ArchiveURL + publication.PagesURL
+		}
+
+		publications = append(publications, publication)
+	}
+
+	return strings.TrimSpace(feed.Metadata.Title), next, publications, nil
+}
+
+// get requests the url with basic auth if configured and returns the body and its content type
+func (o *opds) get(ctx context.Context, u string) ([]byte, string, error) {
+	var body []byte
+	var contentType string
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "mangarr")
+	req.Header.Set("Accept", "application/atom+xml, application/opds+json, application/json;q=0.9, */*;q=0.8")
+	if len(o.Username) != 0 && o.onFeedHost(u) {
+		req.SetBasicAuth(o.Username, o.Password)
+	}
+
+	retryErr := retry.Do(func() error {
+		resp, err := sharedhttp.ExecRequest(*o.Client, req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		body, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+
+		contentType = resp.Header.Get("Content-Type")
+		return nil
+	},
+		retry.Delay(time.Second*3),
+		retry.Attempts(3),
+		retry.MaxJitter(time.Second*1),
+	)
+
+	return body, contentType, retryErr
+}
+
+// headers returns the authorization header to send along with the requests for the images or archive at the urls, the
+// credentials are only sent if all of them are served by the host of the feed so they don't leak to other hosts
+func (o *opds) headers(urls ...string) map[string]string {
+	if len(o.Username) == 0 || len(urls) == 0 {
+		return nil
+	}
+
+	for _, u := range urls {
+		if !o.onFeedHost(u) {
+			return nil
+		}
+	}
+
+	req := http.Request{Header: make(http.Header)}
+	req.SetBasicAuth(o.Username, o.Password)
+
+	return map[string]string{"Authorization": req.Header.Get("Authorization")}
+}
+
+// onFeedHost reports whether the url is served by the host of the feed
+func (o *opds) onFeedHost(u string) bool {
+	feedURL, err := url.Parse(o.FeedURL)
+	if err != nil {
+		return false
+	}
+
+	target, err := url.Parse(u)
+	if err != nil {
+		return false
+	}
+
+	return strings.EqualFold(target.Scheme, feedURL.Scheme) && strings.EqualFold(target.Host, feedURL.Host)
+}
+
+// chapterLabel returns the chapter number in the title of a publication
+func (o *opds) chapterLabel(title string) string {
+	if o.titlePattern != nil {
+		return matchFirstGroup(o.titlePattern, title)
+	}
+
+	if label := matchFirstGroup(defaultFeedTitlePattern, title); len(label) != 0 {
+		return label
+	}
+
+	return matchFirstGroup(opdsNumberPattern, title)
+}
+
+// opdsStripMaxWidth removes the optional maxWidth parameter from a page streaming template
+func opdsStripMaxWidth(template string) string {
+	base, query, ok := strings.Cut(template, "?")
+	if !ok {
+		return strings.ReplaceAll(template, "{maxWidth}", "")
+	}
+
+	var params []string
+	for _, param := range strings.Split(query, "&") {
+		if !strings.Contains(param, "{maxWidth}") {
+			params = append(params, param)
+		}
+	}
+
+	if len(params) == 0 {
+		return base
+	}
+
+	return base + "?" + strings.Join(params, "&")
+}
+
+// opds2Names returns the names of contributors, which may be given as a string, an object or a list of either
+func opds2Names(raw json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	var name string
+	if err := json.Unmarshal(raw, &name); err == nil {
+		return appendValue(nil, name)
+	}
+
+	var contributor struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(raw, &contributor); err == nil {
+		return appendValue(nil, contributor.Name)
+	}
+
+	var list []json.RawMessage
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil
+	}
+
+	var names []string
+	for _, item := range list {
+		for _, name := range opds2Names(item) {
+			names = appendValue(names, name)
+		}
+	}
+
+	return names
+}
+
+// resolveURL resolves the reference against the url of the document it was found in
+func resolveURL(base, ref string) string {
+	ref = strings.TrimSpace(ref)
+
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+
+	// keep templates like {pageNumber} readable instead of escaping their braces
+	resolved := baseURL.ResolveReference(refURL).String()
+	return strings.NewReplacer("%7B", "{", "%7D", "}").Replace(resolved)
+}
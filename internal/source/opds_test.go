@@ -0,0 +1,258 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"mangarr/internal/domain"
+)
+
+const (
+	opdsTestUser     = "reader"
+	opdsTestPassword = "secret"
+)
+
+// opdsTestServers serves a catalog that requires basic auth and a second host standing in for a cdn, which records
+// the authorization headers it was sent
+type opdsTestServers struct {
+	catalog *httptest.Server
+	cdn     *httptest.Server
+
+	mu          sync.Mutex
+	leakedAuths []string
+}
+
+func newOPDSTestServers(t *testing.T) *opdsTestServers {
+	t.Helper()
+
+	s := &opdsTestServers{}
+
+	s.cdn = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); len(auth) != 0 {
+			s.mu.Lock()
+			s.leakedAuths = append(s.leakedAuths, r.URL.Path)
+			s.mu.Unlock()
+		}
+
+		switch r.URL.Path {
+		case "/manifest/3.json":
+			w.Header().Set("Content-Type", opdsTypeDivina)
+			fmt.Fprint(w, `{"readingOrder":[{"href":"pages/1.jpg","width":800,"height":1200},{"href":"pages/2.jpg"}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(s.cdn.Close)
+
+	s.catalog = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); !ok || user != opdsTestUser || password != opdsTestPassword {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		cdnURL := s.cdn.URL
+
+		// pages of the atom feed share their path and are told apart by their query
+		switch r.URL.RequestURI() {
+		case "/opds/v1.2/series/1":
+			w.Header().Set("Content-Type", "application/atom+xml;profile=opds-catalog")
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:pse="http://vaemendis.net/opds-pse/ns">
+  <title>Vinland Saga</title>
+  <link rel="next" href="/opds/v1.2/series/1?page=2" type="application/atom+xml;profile=opds-catalog"/>
+  <entry>
+    <id>urn:book:1</id>
+    <title>Vinland Saga Chapter 1</title>
+    <updated>2024-01-01T00:00:00Z</updated>
+    <author><name>Makoto Yukimura</name></author>
+    <link rel="http://vaemendis.net/opds-pse/stream" type="image/jpeg" pse:count="3" href="/opds/v1.2/books/1/pages/{pageNumber}?zero_based=true&amp;width={maxWidth}"/>
+  </entry>
+  <entry>
+    <id>urn:book:2</id>
+    <title>Vinland Saga Chapter 2</title>
+    <updated>2024-01-08T00:00:00Z</updated>
+    <link rel="http://opds-spec.org/acquisition" type="application/vnd.comicbook+zip" href="/opds/v1.2/books/2/file"/>
+  </entry>
+</feed>`)
+		case "/opds/v1.2/series/1?page=2":
+			w.Header().Set("Content-Type", "application/atom+xml;profile=opds-catalog")
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Vinland Saga</title>
+  <entry>
+    <id>urn:book:5</id>
+    <title>Vinland Saga Chapter 5</title>
+    <published>2024-01-15T00:00:00Z</published>
+    <link rel="http://opds-spec.org/acquisition" type="application/vnd.comicbook+zip" href="/opds/v1.2/books/5/file"/>
+  </entry>
+</feed>`)
+		case "/opds/v2/series/1":
+			w.Header().Set("Content-Type", "application/opds+json")
+			fmt.Fprintf(w, `{
+  "metadata": {"title": "Vinland Saga"},
+  "links": [{"rel": "self", "href": "/opds/v2/series/1"}],
+  "publications": [
+    {
+      "metadata": {"identifier": "urn:book:3", "title": "Vinland Saga Chapter 3", "author": [{"name": "Makoto Yukimura"}]},
+      "links": [{"rel": "http://opds-spec.org/acquisition", "type": %q, "href": %q}]
+    },
+    {
+      "metadata": {"identifier": "urn:book:4", "title": "Vinland Saga Chapter 4", "author": "Makoto Yukimura"},
+      "links": [{"rel": ["http://opds-spec.org/acquisition/open-access", "alternate"], "type": "application/zip", "href": %q}]
+    }
+  ]
+}`, opdsTypeDivina, cdnURL+"/manifest/3.json", cdnURL+"/books/4.cbz")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(s.catalog.Close)
+
+	return s
+}
+
+func (s *opdsTestServers) source(t *testing.T, path string) domain.Source {
+	t.Helper()
+
+	src, err := New("opds", Input{
+		Manga:   s.catalog.URL + path,
+		Options: map[string]string{opdsOptionUsername: opdsTestUser, opdsOptionPassword: opdsTestPassword},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	return src
+}
+
+func (s *opdsTestServers) authHeader() string {
+	req := http.Request{Header: make(http.Header)}
+	req.SetBasicAuth(opdsTestUser, opdsTestPassword)
+	return req.Header.Get("Authorization")
+}
+
+func opdsTestChapter(t *testing.T, manga domain.Manga, number string) domain.Chapter {
+	t.Helper()
+
+	releases := manga.Chapters[domain.ParseChapterNumber(number).Key()]
+	if len(releases) != 1 {
+		t.Fatalf("releases of chapter %s = %d, want 1", number, len(releases))
+	}
+
+	return releases[0]
+}
+
+func TestOPDS1Feed(t *testing.T) {
+	servers := newOPDSTestServers(t)
+	src := servers.source(t, "/opds/v1.2/series/1")
+
+	manga, err := src.GetManga(context.Background())
+	if err != nil {
+		t.Fatalf("GetManga: %v", err)
+	}
+
+	if manga.Title != "Vinland Saga" {
+		t.Errorf("Title = %q, want %q", manga.Title, "Vinland Saga")
+	}
+	if len(manga.Chapters) != 3 {
+		t.Fatalf("chapters = %d, want 3 across both pages", len(manga.Chapters))
+	}
+
+	streamed := opdsTestChapter(t, manga, "1")
+	if err := src.GetImageURLs(context.Background(), &streamed); err != nil {
+		t.Fatalf("GetImageURLs: %v", err)
+	}
+
+	if len(streamed.ImageInfo) != 3 {
+		t.Fatalf("pages = %d, want 3", len(streamed.ImageInfo))
+	}
+	for i, image := range streamed.ImageInfo {
+		want := fmt.Sprintf("%s/opds/v1.2/books/1/pages/%d?zero_based=true", servers.catalog.URL, i)
+		if image.ImageURL != want {
+			t.Errorf("page %d = %q, want %q", i, image.ImageURL, want)
+		}
+	}
+	if streamed.Headers["Authorization"] != servers.authHeader() {
+		t.Errorf("pages on the catalog host have Authorization %q", streamed.Headers["Authorization"])
+	}
+
+	archive := opdsTestChapter(t, manga, "5")
+	if archive.ArchiveURL != servers.catalog.URL+"/opds/v1.2/books/5/file" {
+		t.Errorf("ArchiveURL = %q", archive.ArchiveURL)
+	}
+	if archive.Headers["Authorization"] != servers.authHeader() {
+		t.Errorf("archive on the catalog host has Authorization %q", archive.Headers["Authorization"])
+	}
+}
+
+func TestOPDS2Feed(t *testing.T) {
+	servers := newOPDSTestServers(t)
+	src := servers.source(t, "/opds/v2/series/1")
+
+	manga, err := src.GetManga(context.Background())
+	if err != nil {
+		t.Fatalf("GetManga: %v", err)
+	}
+
+	if len(manga.Metadata.Authors) != 1 || manga.Metadata.Authors[0] != "Makoto Yukimura" {
+		t.Errorf("Authors = %v", manga.Metadata.Authors)
+	}
+
+	divina := opdsTestChapter(t, manga, "3")
+	if err := src.GetImageURLs(context.Background(), &divina); err != nil {
+		t.Fatalf("GetImageURLs: %v", err)
+	}
+
+	if len(divina.ImageInfo) != 2 {
+		t.Fatalf("pages = %d, want 2", len(divina.ImageInfo))
+	}
+	if want := servers.cdn.URL + "/manifest/pages/1.jpg"; divina.ImageInfo[0].ImageURL != want || divina.ImageInfo[0].Width != 800 {
+		t.Errorf("page 1 = %+v, want %s with width 800", divina.ImageInfo[0], want)
+	}
+	if len(divina.Headers) != 0 {
+		t.Errorf("pages on another host have headers %v", divina.Headers)
+	}
+
+	// the acquisition link lists its relations as an array
+	archive := opdsTestChapter(t, manga, "4")
+	if archive.ArchiveURL != servers.cdn.URL+"/books/4.cbz" {
+		t.Errorf("ArchiveURL = %q", archive.ArchiveURL)
+	}
+	if len(archive.Headers) != 0 {
+		t.Errorf("archive on another host has headers %v", archive.Headers)
+	}
+
+	if len(servers.leakedAuths) != 0 {
+		t.Errorf("credentials were sent to another host for %s", strings.Join(servers.leakedAuths, ", "))
+	}
+}
+
+func TestOPDSRequiresCredentials(t *testing.T) {
+	servers := newOPDSTestServers(t)
+
+	src, err := New("opds", Input{Manga: servers.catalog.URL + "/opds/v2/series/1"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if _, err := src.GetManga(context.Background()); err == nil {
+		t.Error("GetManga without credentials should fail")
+	}
+
+	// credentials in the url are used as well
+	withUser := strings.Replace(servers.catalog.URL, "://", "://"+opdsTestUser+":"+opdsTestPassword+"@", 1)
+
+	src, err = New("opds", Input{Manga: withUser + "/opds/v2/series/1"})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if _, err := src.GetManga(context.Background()); err != nil {
+		t.Errorf("GetManga with credentials in the url: %v", err)
+	}
+}
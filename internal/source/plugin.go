@@ -0,0 +1,473 @@
+package source
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"mangarr/internal/domain"
+	"mangarr/internal/sanitize"
+
+	"github.com/rs/zerolog"
+)
+
+// PluginProtocolVersion is the version of the plugin protocol spoken by mangarr.
+//
+// A plugin is started once per operation, it receives a single request as one line of JSON on stdin and has to answer
+// with a single response as one line of JSON on stdout. Everything the plugin writes to stderr is logged by mangarr.
+const PluginProtocolVersion = 1
+
+const defaultPluginTimeout = 60 * time.Second
+
+// plugin methods
+const (
+	pluginValidate     = "validate"
+	pluginGetManga     = "getManga"
+	pluginGetChapters  = "getChapters"
+	pluginGetImageURLs = "getImageURLs"
+)
+
+type pluginRequest struct {
+	Version int            `json:"version"`
+	Method  string         `json:"method"`
+	Input   pluginInput    `json:"input"`
+	Manga   *pluginManga   `json:"manga,omitempty"`
+	Chapter *pluginChapter `json:"chapter,omitempty"`
+}
+
+type pluginInput struct {
+	Manga    string `json:"manga"`
+	Group    string `json:"group,omitempty"`
+	Language string `json:"language,omitempty"`
+}
+
+type pluginResponse struct {
+	Version  int             `json:"version"`
+	Error    string          `json:"error,omitempty"`
+	Manga    *pluginManga    `json:"manga,omitempty"`
+	Chapters []pluginChapter `json:"chapters,omitempty"`
+	Images   []pluginImage   `json:"images,omitempty"`
+	// Headers are sent along with the requests for the images
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+type pluginManga struct {
+	URL      string         `json:"url"`
+	Title    string         `json:"title"`
+	Metadata pluginMetadata `json:"metadata"`
+}
+
+type pluginMetadata struct {
+	AltTitles        []string `json:"altTitles,omitempty"`
+	Authors          []string `json:"authors,omitempty"`
+	Artists          []string `json:"artists,omitempty"`
+	Synopsis         string   `json:"synopsis,omitempty"`
+	Genres           []string `json:"genres,omitempty"`
+	Tags             []string `json:"tags,omitempty"`
+	Status           string   `json:"status,omitempty"`
+	CoverURL         string   `json:"coverUrl,omitempty"`
+	ReadingDirection string   `json:"readingDirection,omitempty"`
+	SourceURL        string   `json:"sourceUrl,omitempty"`
+}
+
+type pluginChapter struct {
+	ID          string            `json:"id,omitempty"`
+	URL         string            `json:"url,omitempty"`
+	Number      string            `json:"number"`
+	Volume      string            `json:"volume,omitempty"`
+	Title       string            `json:"title,omitempty"`
+	PublishedAt time.Time         `json:"publishedAt"`
+	Group       string            `json:"group,omitempty"`
+	Language    string            `json:"language,omitempty"`
+	Pages       int               `json:"pages,omitempty"`
+	Manhwa      bool              `json:"manhwa,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	ArchiveURL  string            `json:"archiveUrl,omitempty"`
+}
+
+type pluginImage struct {
+	URL           string  `json:"url"`
+	EncryptionKey string  `json:"encryptionKey,omitempty"`
+	Width         float64 `json:"width,omitempty"`
+	Height        float64 `json:"height,omitempty"`
+}
+
+type plugin struct {
+	key     string
+	def     *domain.Plugin
+	timeout time.Duration
+	log     zerolog.Logger
+	input   Input
+}
+
+// RegisterPlugins validates the plugin definitions from the config and registers the valid ones as sources
+func RegisterPlugins(defs map[string]*domain.Plugin, log zerolog.Logger) error {
+	var errs []error
+
+	for key, def := range defs {
+		if _, ok := Lookup(key); ok {
+			errs = append(errs, fmt.Errorf("source %q is already registered", key))
+			continue
+		}
+
+		if def == nil || len(def.Command) == 0 {
+			errs = append(errs, fmt.Errorf("invalid plugin %q: command is required", key))
+			continue
+		}
+
+		if _, err := exec.LookPath(def.Command); err != nil {
+			errs = append(errs, fmt.Errorf("invalid plugin %q: %w", key, err))
+			continue
+		}
+
+		timeout := defaultPluginTimeout
+		if def.Timeout > 0 {
+			timeout = time.Duration(def.Timeout) * time.Second
+		}
+
+		pluginLog := log.With().Str("plugin", key).Logger()
+
+		Register(Definition{
+			Key:  key,
+			Name: fmt.Sprintf("%s plugin", key),
+			Fields: []Field{
+				{Name: InputManga, Required: true, Help: "Manga as expected by the plugin"},
+				{Name: InputGroup, Help: "Passed on to the plugin as is"},
+				{Name: InputLanguage, Help: "Passed on to the plugin as is"},
+			},
+			New: func(in Input) domain.Source {
+				return &plugin{
+					key:     key,
+					def:     def,
+					timeout: timeout,
+					log:     pluginLog,
+					input:   in,
+				}
+			},
+		})
+	}
+
+	return errors.Join(errs...)
+}
+
+func (p *plugin) String() string {
+	return p.key
+}
+
+func (p *plugin) ValidateInput() error {
+	_, err := p.call(context.Background(), pluginRequest{Method: pluginValidate})
+	return err
+}
+
+func (p *plugin) GetManga(ctx context.Context) (domain.Manga, error) {
+	resp, err := p.call(ctx, pluginRequest{Method: pluginGetManga})
+	if err != nil {
+		return domain.Manga{}, err
+	}
+
+	if resp.Manga == nil {
+		return domain.Manga{}, fmt.Errorf("failed to get manga for provided input: %s", p.input.Manga)
+	}
+
+	manga := resp.Manga.toDomain()
+	if len(manga.Title) == 0 {
+		return domain.Manga{}, fmt.Errorf("failed to get manga for provided input: %s", p.input.Manga)
+	}
+
+	addPluginChapters(manga, resp.Chapters)
+
+	return manga, nil
+}
+
+func (p *plugin) GetChapters(ctx context.Context, manga domain.Manga) error {
+	resp, err := p.call(ctx, pluginRequest{
+		Method: pluginGetChapters,
+		Manga:  newPluginManga(manga),
+	})
+	if err != nil {
+		return err
+	}
+
+	addPluginChapters(manga, resp.Chapters)
+
+	if len(manga.Chapters) == 0 {
+		return fmt.Errorf("failed to get chapters for manga: %s", manga.Title)
+	}
+
+	return nil
+}
+
+func (p *plugin) GetImageURLs(ctx context.Context, chapter *domain.Chapter) error {
+	// chapters with an archive are downloaded as is
+	if len(chapter.ArchiveURL) != 0 {
+		return nil
+	}
+
+	resp, err := p.call(ctx, pluginRequest{
+		Method:  pluginGetImageURLs,
+		Chapter: newPluginChapter(*chapter),
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(resp.Headers) != 0 {
+		chapter.Headers = resp.Headers
+	}
+
+	if len(resp.Images) == 0 {
+		return fmt.Errorf("failed to get image urls for chapter number: %s", chapter.Number)
+	}
+
+	imageInfos := make([]domain.ImageInfo, 0, len(resp.Images))
+	for _, image := range resp.Images {
+		imageInfos = append(imageInfos, domain.ImageInfo{
+			ImageURL:      image.URL,
+			EncryptionKey: image.EncryptionKey,
+			Width:         image.Width,
+			Height:        image.Height,
+		})
+	}
+
+	chapter.ImageInfo = imageInfos
+	return nil
+}
+
+// call runs the plugin for a single request and returns its response, the plugin is killed once the timeout is
+// reached or ctx is cancelled
+func (p *plugin) call(ctx context.Context, req pluginRequest) (pluginResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	req.Version = PluginProtocolVersion
+	req.Input = pluginInput{
+		Manga:    p.input.Manga,
+		Group:    p.input.Group,
+		Language: p.input.Language,
+	}
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return pluginResponse{}, fmt.Errorf("failed to encode %s request: %w", req.Method, err)
+	}
+
+	stderr := &logWriter{log: p.log.With().Str("method", req.Method).Logger()}
+	defer stderr.Flush()
+
+	var stdout bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, p.def.Command, p.def.Args...)
+	cmd.Stdin = bytes.NewReader(append(payload, '\n'))
+	cmd.Stdout = &stdout
+	cmd.Stderr = stderr
+	cmd.WaitDelay = 5 * time.Second
+
+	if err := cmd.Run(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			if errors.Is(ctxErr, context.DeadlineExceeded) {
+				return pluginResponse{}, fmt.Errorf("plugin %s timed out after %s during %s", p.key, p.timeout, req.Method)
+			}
+			return pluginResponse{}, ctxErr
+		}
+
+		// a plugin may exit with an error after reporting it in its response
+		if resp, decodeErr := p.decode(stdout.Bytes()); decodeErr == nil && len(resp.Error) != 0 {
+			return pluginResponse{}, errors.New(resp.Error)
+		}
+
+		return pluginResponse{}, fmt.Errorf("plugin %s failed during %s: %w", p.key, req.Method, err)
+	}
+
+	resp, err := p.decode(stdout.Bytes())
+	if err != nil {
+		return pluginResponse{}, fmt.Errorf("plugin %s returned an invalid %s response: %w", p.key, req.Method, err)
+	}
+
+	if len(resp.Error) != 0 {
+		return pluginResponse{}, errors.New(resp.Error)
+	}
+
+	return resp, nil
+}
+
+// decode reads the response from the first non-empty line of output
+func (p *plugin) decode(output []byte) (pluginResponse, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var resp pluginResponse
+		if err := json.Unmarshal(line, &resp); err != nil {
+			return pluginResponse{}, err
+		}
+
+		if resp.Version != PluginProtocolVersion {
+			return pluginResponse{}, fmt.Errorf("unsupported protocol version %d, expected %d", resp.Version, PluginProtocolVersion)
+		}
+
+		return resp, nil
+	}
+
+	if err := scanner.Err(); err != nil {
+		return pluginResponse{}, err
+	}
+
+	return pluginResponse{}, fmt.Errorf("no response")
+}
+
+func newPluginManga(manga domain.Manga) *pluginManga {
+	m := manga.Metadata
+
+	return &pluginManga{
+		URL:   manga.URL,
+		Title: manga.Title,
+		Metadata: pluginMetadata{
+			AltTitles:        m.AltTitles,
+			Authors:          m.Authors,
+			Artists:          m.Artists,
+			Synopsis:         m.Synopsis,
+			Genres:           m.Genres,
+			Tags:             m.Tags,
+			Status:           string(m.Status),
+			CoverURL:         m.CoverURL,
+			ReadingDirection: string(m.ReadingDirection),
+			SourceURL:        m.SourceURL,
+		},
+	}
+}
+
+func (m *pluginManga) toDomain() domain.Manga {
+	return domain.Manga{
+		URL:   m.URL,
+		Title: sanitize.Filename(m.Title),
+		Metadata: domain.Metadata{
+			AltTitles:        m.Metadata.AltTitles,
+			Authors:          m.Metadata.Authors,
+			Artists:          m.Metadata.Artists,
+			Synopsis:         m.Metadata.Synopsis,
+			Genres:           m.Metadata.Genres,
+			Tags:             m.Metadata.Tags,
+			Status:           domain.ParsePublicationStatus(m.Metadata.Status),
+			CoverURL:         m.Metadata.CoverURL,
+			ReadingDirection: domain.ReadingDirection(m.Metadata.ReadingDirection),
+			SourceURL:        m.Metadata.SourceURL,
+		},
+		Chapters: make(map[string][]domain.Chapter),
+	}
+}
+
+func newPluginChapter(chapter domain.Chapter) *pluginChapter {
+	return &pluginChapter{
+		ID:          chapter.ID,
+		URL:         chapter.URL,
+		Number:      chapter.Number.Label,
+		Volume:      chapter.Volume,
+		Title:       chapter.Title,
+		PublishedAt: chapter.PublishedAt,
+		Group:       chapter.Group,
+		Language:    chapter.Language,
+		Pages:       chapter.Pages,
+		Manhwa:      chapter.IsManhwa,
+		Headers:     chapter.Headers,
+		ArchiveURL:  chapter.ArchiveURL,
+	}
+}
+
+func (c pluginChapter) toDomain() domain.Chapter {
+	return domain.Chapter{
+		ID:          c.ID,
+		URL:         c.URL,
+		Number:      domain.ParseChapterNumber(c.Number),
+		Volume:      c.Volume,
+		Title:       sanitize.Filename(c.Title),
+		PublishedAt: c.PublishedAt,
+		Group:       c.Group,
+		Language:    c.Language,
+		Pages:       c.Pages,
+		IsManhwa:    c.Manhwa,
+		Headers:     c.Headers,
+		ArchiveURL:  c.ArchiveURL,
+	}
+}
+
+// addPluginChapters adds the chapters to the manga, chapters a plugin returns from both getManga and getChapters are
+// only added once
+func addPluginChapters(manga domain.Manga, chapters []pluginChapter) {
+	for _, c := range chapters {
+		chapter := c.toDomain()
+		if slices.ContainsFunc(manga.Chapters[chapter.Number.Key()], func(release domain.Chapter) bool {
+			return samePluginRelease(release, chapter)
+		}) {
+			continue
+		}
+
+		manga.AddChapter(chapter)
+	}
+}
+
+// samePluginRelease reports whether two chapters of the same number are the same release, by their ID if both have
+// one and by where they are read from otherwise
+func samePluginRelease(a, b domain.Chapter) bool {
+	if len(a.ID) != 0 && len(b.ID) != 0 {
+		return a.ID == b.ID
+	}
+
+	return a.URL == b.URL && a.ArchiveURL == b.ArchiveURL && a.Group == b.Group && a.Language == b.Language
+}
+
+// logWriter logs every line written to it
+type logWriter struct {
+	log zerolog.Logger
+	mu  sync.Mutex
+	buf []byte
+}
+
+func (w *logWriter) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, b...)
+
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+
+		w.logLine(string(w.buf[:i]))
+		w.buf = w.buf[i+1:]
+	}
+
+	return len(b), nil
+}
+
+// Flush logs any remaining partial line
+func (w *logWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.logLine(string(w.buf))
+	w.buf = nil
+}
+
+func (w *logWriter) logLine(line string) {
+	line = strings.TrimSpace(line)
+	if len(line) == 0 {
+		return
+	}
+
+	w.log.Debug().Msg(line)
+}
@@ -0,0 +1,41 @@
+package source
+
+import (
+	"testing"
+)
+
+func TestPluginTitlesAreSanitized(t *testing.T) {
+	manga := (&pluginManga{Title: "../../Solo Leveling"}).toDomain()
+	if manga.Title != "..Solo Leveling" {
+		t.Errorf("manga Title = %q, want %q", manga.Title, "..Solo Leveling")
+	}
+
+	chapter := pluginChapter{Number: "1", Title: "a/../../b"}.toDomain()
+	if chapter.Title != "a....b" {
+		t.Errorf("chapter Title = %q, want %q", chapter.Title, "a....b")
+	}
+}
+
+func TestAddPluginChaptersDedupes(t *testing.T) {
+	manga := (&pluginManga{Title: "Solo Leveling"}).toDomain()
+
+	fromManga := []pluginChapter{
+		{ID: "a", Number: "1", Group: "One"},
+		{URL: "https://example.com/2", Number: "2"},
+	}
+	fromChapters := []pluginChapter{
+		{ID: "a", Number: "1", Group: "One"},
+		{ID: "b", Number: "1", Group: "Two"},
+		{URL: "https://example.com/2", Number: "2"},
+	}
+
+	addPluginChapters(manga, fromManga)
+	addPluginChapters(manga, fromChapters)
+
+	if got := len(manga.Chapters["1"]); got != 2 {
+		t.Errorf("releases of chapter 1 = %d, want 2", got)
+	}
+	if got := len(manga.Chapters["2"]); got != 1 {
+		t.Errorf("releases of chapter 2 = %d, want 1", got)
+	}
+}
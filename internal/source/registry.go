@@ -0,0 +1,224 @@
+package source
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"mangarr/internal/domain"
+)
+
+// InputField names a value a user can pass to a source
+type InputField string
+
+const (
+	InputManga    InputField = "manga"
+	InputGroup    InputField = "group"
+	InputLanguage InputField = "language"
+)
+
+// Input holds the user supplied values a source is constructed from
+type Input struct {
+	Manga    string
+	Group    string
+	Language string
+	// Options holds source specific settings, their names are matched case-insensitively
+	Options map[string]string
+}
+
+// Value returns the value of the given input field
+func (in Input) Value(field InputField) string {
+	switch field {
+	case InputManga:
+		return in.Manga
+	case InputGroup:
+		return in.Group
+	case InputLanguage:
+		return in.Language
+	default:
+		return ""
+	}
+}
+
+// Option returns the value of the option with the given name
+func (in Input) Option(name string) string {
+	for key, value := range in.Options {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// Field describes how a source makes use of an input field
+type Field struct {
+	Name     InputField
+	Required bool
+	// Help describes the value the source expects
+	Help string
+	// Example is a value the source accepts
+	Example string
+}
+
+// Option describes a source specific setting
+type Option struct {
+	Name    string
+	Help    string
+	Example string
+}
+
+// Definition describes a source, its capabilities and how to construct it
+type Definition struct {
+	Key string
+	// Name is the display name of the site
+	Name   string
+	Fields []Field
+	// Options lists the source specific options the source accepts
+	Options []Option
+	// Languages lists the languages the source releases chapters in, it is empty if they are unknown or any language
+	// can be requested with the language field
+	Languages []string
+	// Manhwa is set if the source hosts long strip manhwa
+	Manhwa bool
+	// Auth describes the authentication the source needs, it is empty if none is needed
+	Auth string
+	// RateLimit describes the rate limit requests to the source are subject to, it is empty if there is none
+	RateLimit string
+	New       func(Input) domain.Source
+}
+
+// Accepts reports whether the source makes use of the given input field
+func (d Definition) Accepts(field InputField) bool {
+	_, ok := d.Field(field)
+	return ok
+}
+
+// Field returns the description of the given input field
+func (d Definition) Field(field InputField) (Field, bool) {
+	for _, f := range d.Fields {
+		if f.Name == field {
+			return f, true
+		}
+	}
+
+	return Field{}, false
+}
+
+// AcceptsOption reports whether the source accepts the option with the given name
+func (d Definition) AcceptsOption(name string) bool {
+	for _, option := range d.Options {
+		if strings.EqualFold(option.Name, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Searchable reports whether the source supports searching
+func (d Definition) Searchable() bool {
+	_, ok := d.New(Input{}).(domain.Searcher)
+	return ok
+}
+
+// InputError is returned when the input is invalid for a source
+type InputError struct {
+	Source string
+	Err    error
+}
+
+func (e *InputError) Error() string {
+	return fmt.Sprintf("invalid input for %s: %v, run \"mangarr sources %s\" to see what it expects", e.Source, e.Err, e.Source)
+}
+
+func (e *InputError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	registry   = make(map[string]Definition)
+	registryMu sync.RWMutex
+)
+
+// Register adds a source definition to the registry, it panics if the key is already taken
+func Register(def Definition) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, ok := registry[def.Key]; ok {
+		panic(fmt.Sprintf("source %q is already registered", def.Key))
+	}
+
+	registry[def.Key] = def
+}
+
+// Lookup returns the definition registered for key
+func Lookup(key string) (Definition, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	def, ok := registry[key]
+	return def, ok
+}
+
+// Keys returns the keys of all registered sources in alphabetical order
+func Keys() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	keys := make([]string, 0, len(registry))
+	for key := range registry {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
+
+// New constructs the source registered for key and validates its input
+func New(key string, in Input) (domain.Source, error) {
+	def, ok := Lookup(key)
+	if !ok {
+		return nil, unknownSourceError(key)
+	}
+
+	for _, field := range def.Fields {
+		if field.Required && len(strings.TrimSpace(in.Value(field.Name))) == 0 {
+			return nil, &InputError{Source: key, Err: fmt.Errorf("%s is required", field.Name)}
+		}
+	}
+
+	for name := range in.Options {
+		if !def.AcceptsOption(name) {
+			return nil, &InputError{Source: key, Err: fmt.Errorf("unknown option %q", name)}
+		}
+	}
+
+	s := def.New(in)
+	if err := s.ValidateInput(); err != nil {
+		return nil, &InputError{Source: key, Err: err}
+	}
+
+	return s, nil
+}
+
+// NewSearcher constructs the source registered for key for searching, it fails if the source does not support it
+func NewSearcher(key string) (domain.Searcher, error) {
+	def, ok := Lookup(key)
+	if !ok {
+		return nil, unknownSourceError(key)
+	}
+
+	searcher, ok := def.New(Input{}).(domain.Searcher)
+	if !ok {
+		return nil, fmt.Errorf("source %q does not support searching", key)
+	}
+
+	return searcher, nil
+}
+
+func unknownSourceError(key string) error {
+	return fmt.Errorf("unknown source %q, available sources: %s, run \"mangarr sources\" for details", key, strings.Join(Keys(), ", "))
+}
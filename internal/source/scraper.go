@@ -0,0 +1,312 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"mangarr/internal/domain"
+	"mangarr/internal/sanitize"
+
+	"github.com/gocolly/colly"
+)
+
+var defaultChapterNumberPattern = regexp.MustCompile(`(\d+(?:\.\d+)?[a-z]?)`)
+
+// scraperField is a ScraperField with its pattern compiled
+type scraperField struct {
+	domain.ScraperField
+	pattern *regexp.Regexp
+}
+
+// scraperConfig is a validated ScraperSource
+type scraperConfig struct {
+	key           string
+	def           *domain.ScraperSource
+	title         scraperField
+	chapterURL    scraperField
+	chapterNumber scraperField
+	chapterTitle  scraperField
+}
+
+type scraper struct {
+	MangaURL string
+	config   *scraperConfig
+}
+
+// RegisterScrapers validates the scraper definitions from the config and registers the valid ones as sources
+func RegisterScrapers(defs map[string]*domain.ScraperSource) error {
+	var errs []error
+
+	for key, def := range defs {
+		if _, ok := Lookup(key); ok {
+			errs = append(errs, fmt.Errorf("source %q is already registered", key))
+			continue
+		}
+
+		cfg, err := newScraperConfig(key, def)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid source %q: %w", key, err))
+			continue
+		}
+
+		help := fmt.Sprintf("URL of the manga on %s", cfg.name())
+		if len(def.URLPrefix) != 0 {
+			help += fmt.Sprintf(", must start with %s", def.URLPrefix)
+		}
+
+		var languages []string
+		if len(def.Language) != 0 {
+			languages = []string{def.Language}
+		}
+
+		Register(Definition{
+			Key:  key,
+			Name: cfg.name(),
+			Fields: []Field{
+				{Name: InputManga, Required: true, Help: help},
+			},
+			Languages: languages,
+			Manhwa:    def.Manhwa,
+			New: func(in Input) domain.Source {
+				return &scraper{
+					MangaURL: in.Manga,
+					config:   cfg,
+				}
+			},
+		})
+	}
+
+	return errors.Join(errs...)
+}
+
+func newScraperConfig(key string, def *domain.ScraperSource) (*scraperConfig, error) {
+	if def == nil {
+		return nil, fmt.Errorf("definition is empty")
+	}
+
+	if len(def.Title.Selector) == 0 {
+		return nil, fmt.Errorf("title selector is required")
+	}
+
+	if len(def.Chapters.Selector) == 0 {
+		return nil, fmt.Errorf("chapters selector is required")
+	}
+
+	if len(def.Images.Selector) == 0 {
+		return nil, fmt.Errorf("images selector is required")
+	}
+
+	cfg := &scraperConfig{key: key, def: def}
+
+	fields := []struct {
+		name   string
+		target *scraperField
+		field  domain.ScraperField
+	}{
+		{"title", &cfg.title, def.Title},
+		{"chapters url", &cfg.chapterURL, def.Chapters.URL},
+		{"chapters number", &cfg.chapterNumber, def.Chapters.Number},
+		{"chapters title", &cfg.chapterTitle, def.Chapters.Title},
+	}
+
+	for _, f := range fields {
+		*f.target = scraperField{ScraperField: f.field}
+
+		if len(f.field.Pattern) == 0 {
+			continue
+		}
+
+		pattern, err := regexp.Compile(f.field.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s pattern: %w", f.name, err)
+		}
+
+		f.target.pattern = pattern
+	}
+
+	// the title selector matches the title element itself
+	cfg.title.Selector = ""
+
+	// chapters link to their page themselves unless told otherwise
+	if len(cfg.chapterURL.Selector) == 0 && len(cfg.chapterURL.Attr) == 0 {
+		cfg.chapterURL.Attr = "href"
+	}
+
+	// without any configuration the number is taken from the text of the chapter element
+	if cfg.chapterNumber.ScraperField == (domain.ScraperField{}) {
+		cfg.chapterNumber.pattern = defaultChapterNumberPattern
+	}
+
+	return cfg, nil
+}
+
+func (c *scraperConfig) name() string {
+	if len(c.def.Name) != 0 {
+		return c.def.Name
+	}
+
+	return c.key
+}
+
+func (s *scraper) String() string {
+	return s.config.name()
+}
+
+func (s *scraper) ValidateInput() error {
+	if len(s.MangaURL) == 0 {
+		return fmt.Errorf("manga url is required")
+	}
+
+	if !strings.HasPrefix(s.MangaURL, s.config.def.URLPrefix) {
+		return fmt.Errorf("the url for %s must start with %s", s, s.config.def.URLPrefix)
+	}
+
+	if _, err := url.Parse(s.MangaURL); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *scraper) GetManga(ctx context.Context) (domain.Manga, error) {
+	def := s.config.def
+
+	manga := domain.Manga{
+		URL: s.MangaURL,
+		Metadata: domain.Metadata{
+			SourceURL: s.MangaURL,
+		},
+		Chapters: make(map[string][]domain.Chapter),
+	}
+
+	if def.Manhwa {
+		manga.Metadata.ReadingDirection = domain.DirectionVertical
+	}
+
+	c := newCollector(ctx)
+
+	c.OnHTML(def.Title.Selector, func(e *colly.HTMLElement) {
+		if len(manga.Title) == 0 {
+			manga.Title = sanitize.Filename(s.config.title.extract(e, true))
+		}
+	})
+
+	c.OnHTML(def.Chapters.Selector, func(e *colly.HTMLElement) {
+		label := s.config.chapterNumber.extract(e, false)
+		if len(label) == 0 {
+			return
+		}
+
+		chapterURL := s.config.chapterURL.extract(e, false)
+		if len(chapterURL) == 0 {
+			return
+		}
+
+		manga.AddChapter(domain.Chapter{
+			URL:      e.Request.AbsoluteURL(chapterURL),
+			Number:   domain.ParseChapterNumber(label),
+			Title:    sanitize.Filename(s.config.chapterTitle.extract(e, false)),
+			Group:    s.String(),
+			Language: def.Language,
+			IsManhwa: def.Manhwa,
+		})
+	})
+
+	err := visit(ctx, c, s.MangaURL)
+	if err != nil {
+		return domain.Manga{}, err
+	}
+
+	if len(manga.Title) == 0 {
+		return domain.Manga{}, fmt.Errorf("failed to get manga for provided url: %s", s.MangaURL)
+	}
+
+	if len(manga.Chapters) == 0 {
+		return domain.Manga{}, fmt.Errorf("failed to get chapters for manga: %s", manga.Title)
+	}
+
+	return manga, nil
+}
+
+func (s *scraper) GetChapters(_ context.Context, _ domain.Manga) error {
+	return nil
+}
+
+func (s *scraper) GetImageURLs(ctx context.Context, chapter *domain.Chapter) error {
+	images := s.config.def.Images
+
+	attr := images.Attr
+	if len(attr) == 0 {
+		attr = "src"
+	}
+
+	c := newCollector(ctx)
+
+	var imageInfos []domain.ImageInfo
+
+	c.OnHTML(images.Selector, func(e *colly.HTMLElement) {
+		imgURL := strings.TrimSpace(e.Attr(attr))
+		if len(imgURL) == 0 {
+			return
+		}
+
+		imgURL = e.Request.AbsoluteURL(imgURL)
+
+		if len(images.Prefixes) != 0 && !hasAnyPrefix(imgURL, images.Prefixes) {
+			return
+		}
+
+		imageInfos = append(imageInfos, domain.ImageInfo{ImageURL: imgURL})
+	})
+
+	err := visit(ctx, c, chapter.URL)
+	if err != nil {
+		return err
+	}
+
+	if len(imageInfos) == 0 {
+		return fmt.Errorf("failed to get image urls for chapter number: %s", chapter.Number)
+	}
+
+	chapter.ImageInfo = imageInfos
+	return nil
+}
+
+// extract gets the value of the field from e, the text of the whole element is used if the field is empty
+// and useText is set, otherwise an empty field yields nothing
+func (f scraperField) extract(e *colly.HTMLElement, useText bool) string {
+	var value string
+
+	switch {
+	case len(f.Selector) != 0 && len(f.Attr) != 0:
+		value = e.ChildAttr(f.Selector, f.Attr)
+	case len(f.Selector) != 0:
+		value = e.ChildText(f.Selector)
+	case len(f.Attr) != 0:
+		value = e.Attr(f.Attr)
+	case useText || f.pattern != nil:
+		value = e.Text
+	}
+
+	value = strings.TrimSpace(value)
+
+	if f.pattern != nil {
+		return matchFirstGroup(f.pattern, value)
+	}
+
+	return value
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
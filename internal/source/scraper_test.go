@@ -0,0 +1,170 @@
+package source
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"mangarr/internal/domain"
+)
+
+func newTestScraper(t *testing.T, mangaURL string, def *domain.ScraperSource) *scraper {
+	t.Helper()
+
+	cfg, err := newScraperConfig("mysite", def)
+	if err != nil {
+		t.Fatalf("newScraperConfig: %v", err)
+	}
+
+	return &scraper{MangaURL: mangaURL, config: cfg}
+}
+
+func TestScraperGetManga(t *testing.T) {
+	server := httptest.NewServer(http.FileServer(http.Dir(filepath.Join("testdata", "scraper"))))
+	defer server.Close()
+
+	s := newTestScraper(t, server.URL+"/series.html", &domain.ScraperSource{
+		Name:     "My Site",
+		Language: "en",
+		Title:    domain.ScraperField{Selector: ".entry-title"},
+		Chapters: domain.ScraperChapters{
+			Selector: ".eplister li",
+			URL:      domain.ScraperField{Selector: "a", Attr: "href"},
+			Number:   domain.ScraperField{Attr: "data-num"},
+			Title:    domain.ScraperField{Selector: ".chapternum", Pattern: `Chapter \S+ - (.+)`},
+		},
+		Images: domain.ScraperImages{Selector: "#readerarea img"},
+	})
+
+	manga, err := s.GetManga(context.Background())
+	if err != nil {
+		t.Fatalf("GetManga: %v", err)
+	}
+
+	if manga.Title != "Solo Leveling" {
+		t.Errorf("Title = %q, want %q", manga.Title, "Solo Leveling")
+	}
+
+	want := map[string]struct {
+		url   string
+		title string
+	}{
+		"2":   {url: server.URL + "/chapter.html?n=2", title: "The Weakest Hunter"},
+		"1":   {url: server.URL + "/chapter.html?n=1", title: "I'm Used to It"},
+		"0.5": {url: "https://example.org/chapter-0.5"},
+	}
+
+	if len(manga.Chapters) != len(want) {
+		t.Errorf("chapters = %d, want %d", len(manga.Chapters), len(want))
+	}
+
+	for key, w := range want {
+		releases := manga.Chapters[key]
+		if len(releases) != 1 {
+			t.Errorf("releases of chapter %s = %d, want 1", key, len(releases))
+			continue
+		}
+
+		chapter := releases[0]
+		if chapter.URL != w.url {
+			t.Errorf("chapter %s: URL = %q, want %q", key, chapter.URL, w.url)
+		}
+		if chapter.Title != w.title {
+			t.Errorf("chapter %s: Title = %q, want %q", key, chapter.Title, w.title)
+		}
+		if chapter.Group != "My Site" || chapter.Language != "en" {
+			t.Errorf("chapter %s: Group = %q, Language = %q", key, chapter.Group, chapter.Language)
+		}
+	}
+}
+
+func TestScraperDefaultChapterNumber(t *testing.T) {
+	server := httptest.NewServer(http.FileServer(http.Dir(filepath.Join("testdata", "scraper"))))
+	defer server.Close()
+
+	// without a number field the number is taken from the text of the chapter element
+	s := newTestScraper(t, server.URL+"/series.html", &domain.ScraperSource{
+		Title: domain.ScraperField{Selector: ".entry-title"},
+		Chapters: domain.ScraperChapters{
+			Selector: ".eplister li",
+			URL:      domain.ScraperField{Selector: "a", Attr: "href"},
+		},
+		Images: domain.ScraperImages{Selector: "#readerarea img"},
+	})
+
+	manga, err := s.GetManga(context.Background())
+	if err != nil {
+		t.Fatalf("GetManga: %v", err)
+	}
+
+	var numbers []string
+	for _, number := range manga.ChapterNumbers() {
+		numbers = append(numbers, number.String())
+	}
+
+	if want := []string{"0.5", "1", "2"}; !slices.Equal(numbers, want) {
+		t.Errorf("chapter numbers = %v, want %v", numbers, want)
+	}
+}
+
+func TestScraperGetImageURLs(t *testing.T) {
+	server := httptest.NewServer(http.FileServer(http.Dir(filepath.Join("testdata", "scraper"))))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		prefixes []string
+		want     []string
+	}{
+		{
+			name: "all images",
+			want: []string{
+				server.URL + "/images/001.jpg",
+				server.URL + "/images/002.jpg",
+				"https://ads.example.org/banner.jpg",
+				server.URL + "/images/003.jpg",
+			},
+		},
+		{
+			name:     "prefixes",
+			prefixes: []string{server.URL},
+			want: []string{
+				server.URL + "/images/001.jpg",
+				server.URL + "/images/002.jpg",
+				server.URL + "/images/003.jpg",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestScraper(t, server.URL+"/series.html", &domain.ScraperSource{
+				Title:    domain.ScraperField{Selector: ".entry-title"},
+				Chapters: domain.ScraperChapters{Selector: ".eplister li"},
+				Images:   domain.ScraperImages{Selector: "#readerarea img", Prefixes: tt.prefixes},
+			})
+
+			chapter := domain.Chapter{URL: server.URL + "/chapter.html", Number: domain.ParseChapterNumber("1")}
+			if err := s.GetImageURLs(context.Background(), &chapter); err != nil {
+				t.Fatalf("GetImageURLs: %v", err)
+			}
+
+			if got := imageInfoURLs(chapter.ImageInfo); !slices.Equal(got, tt.want) {
+				t.Errorf("images = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// imageInfoURLs returns the urls of the images
+func imageInfoURLs(imageInfos []domain.ImageInfo) []string {
+	urls := make([]string, 0, len(imageInfos))
+	for _, imageInfo := range imageInfos {
+		urls = append(urls, imageInfo.ImageURL)
+	}
+
+	return urls
+}
@@ -5,38 +5,42 @@ import (
 	"fmt"
 	"net/url"
 	"regexp"
-	"strconv"
+	"slices"
 	"strings"
-	"time"
 
 	"mangarr/internal/domain"
 	"mangarr/internal/sanitize"
 
 	"github.com/gocolly/colly"
-	"github.com/gocolly/colly/extensions"
 )
 
 const (
 	tcbscansURL = "https://tcbscans.me"
 )
 
-var chapterNumberPattern = regexp.MustCompile(`Chapter (\d+(\.\d+)?)`)
+// chapterNumberPattern captures the chapter number including a letter suffix, e.g. "10a"
+var chapterNumberPattern = regexp.MustCompile(`Chapter (\d+(?:\.\d+)?(?:[a-z]\b)?)`)
 
 type tcbscans struct {
 	MangaTitle string
-	Collector  colly.Collector
 }
 
-func NewTCBScans(mangaTitle string) domain.Source {
-	collector := colly.NewCollector(
-		colly.AllowURLRevisit(),
-	)
-	extensions.RandomUserAgent(collector)
-
-	collector.SetRequestTimeout(120 * time.Second)
+func init() {
+	Register(Definition{
+		Key:  "tcbscans",
+		Name: "TCB Scans",
+		Fields: []Field{
+			{Name: InputManga, Required: true, Help: "Name of the manga exactly as listed on https://tcbscans.me/projects", Example: "One Piece"},
+		},
+		Languages: []string{"en"},
+		New: func(in Input) domain.Source {
+			return NewTCBScans(in.Manga)
+		},
+	})
+}
 
+func NewTCBScans(mangaTitle string) domain.Source {
 	return &tcbscans{
-		Collector:  *collector,
 		MangaTitle: mangaTitle,
 	}
 }
@@ -54,42 +58,94 @@ func (t *tcbscans) ValidateInput() error {
 }
 
 // GetManga gets the selected manga from TCB Scans
-func (t *tcbscans) GetManga(_ context.Context) (domain.Manga, error) {
+func (t *tcbscans) GetManga(ctx context.Context) (domain.Manga, error) {
+	mangas, err := t.getProjects(ctx)
+	if err != nil {
+		return domain.Manga{}, err
+	}
+
+	selectedManga, ok := mangas[t.MangaTitle]
+	if !ok {
+		return domain.Manga{}, fmt.Errorf("failed to get manga for provided name: %s", t.MangaTitle)
+	}
+
+	return selectedManga, nil
+}
+
+// Search finds all projects on TCB Scans whose name contains the query
+func (t *tcbscans) Search(ctx context.Context, query string) ([]domain.SearchResult, error) {
+	mangas, err := t.getProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query = strings.ToLower(query)
+
+	var results []domain.SearchResult
+
+	for name, manga := range mangas {
+		if !strings.Contains(strings.ToLower(name), query) {
+			continue
+		}
+
+		results = append(results, domain.SearchResult{
+			Title:    name,
+			ID:       name,
+			URL:      manga.Metadata.SourceURL,
+			CoverURL: manga.Metadata.CoverURL,
+			Language: "en",
+		})
+	}
+
+	slices.SortFunc(results, func(a, b domain.SearchResult) int {
+		return strings.Compare(a.Title, b.Title)
+	})
+
+	return results, nil
+}
+
+// getProjects gets all projects listed on TCB Scans mapped by their name
+func (t *tcbscans) getProjects(ctx context.Context) (map[string]domain.Manga, error) {
 	mangas := make(map[string]domain.Manga)
-	c := t.Collector.Clone()
+	c := newCollector(ctx)
 
 	c.OnHTML("div.bg-card.border.border-border.rounded.p-3.mb-3", func(e *colly.HTMLElement) {
 		mangaURL := e.ChildAttr("a", "href")
 		name := e.ChildAttr("img", "alt")
 
+		sourceURL, err := url.JoinPath(tcbscansURL, mangaURL)
+		if err != nil {
+			return
+		}
+
 		mangas[name] = domain.Manga{
-			URL:      mangaURL,
-			Title:    sanitize.Filename(name),
-			Chapters: make(map[float32]domain.Chapter),
+			URL:   mangaURL,
+			Title: sanitize.Filename(name),
+			Metadata: domain.Metadata{
+				CoverURL:         e.ChildAttr("img", "src"),
+				ReadingDirection: domain.DirectionRightToLeft,
+				SourceURL:        sourceURL,
+			},
+			Chapters: make(map[string][]domain.Chapter),
 		}
 	})
 
 	path, err := url.JoinPath(tcbscansURL, "projects")
 	if err != nil {
-		return domain.Manga{}, err
+		return nil, err
 	}
 
-	err = c.Visit(path)
+	err = visit(ctx, c, path)
 	if err != nil {
-		return domain.Manga{}, err
-	}
-
-	selectedManga, ok := mangas[t.MangaTitle]
-	if !ok {
-		return domain.Manga{}, fmt.Errorf("failed to get manga for provided name: %s", t.MangaTitle)
+		return nil, err
 	}
 
-	return selectedManga, nil
+	return mangas, nil
 }
 
 // GetChapters gets all chapters for a manga
-func (t *tcbscans) GetChapters(_ context.Context, manga domain.Manga) error {
-	c := t.Collector.Clone()
+func (t *tcbscans) GetChapters(ctx context.Context, manga domain.Manga) error {
+	c := newCollector(ctx)
 
 	c.OnHTML("a.block.border.border-border.bg-card.mb-3.p-3.rounded", func(e *colly.HTMLElement) {
 		chapterURL := e.Attr("href")
@@ -102,11 +158,13 @@ func (t *tcbscans) GetChapters(_ context.Context, manga domain.Manga) error {
 
 		title := sanitize.Filename(e.ChildText("div.text-gray-500"))
 
-		manga.Chapters[number] = domain.Chapter{
-			URL:    chapterURL,
-			Number: number,
-			Title:  title,
-		}
+		manga.AddChapter(domain.Chapter{
+			URL:      chapterURL,
+			Number:   number,
+			Title:    title,
+			Group:    "TCB Scans",
+			Language: "en",
+		})
 	})
 
 	path, err := url.JoinPath(tcbscansURL, manga.URL)
@@ -114,7 +172,7 @@ func (t *tcbscans) GetChapters(_ context.Context, manga domain.Manga) error {
 		return err
 	}
 
-	err = c.Visit(path)
+	err = visit(ctx, c, path)
 	if err != nil {
 		return err
 	}
@@ -127,8 +185,8 @@ func (t *tcbscans) GetChapters(_ context.Context, manga domain.Manga) error {
 }
 
 // GetImageURLs gets all image urls for a chapter
-func (t *tcbscans) GetImageURLs(_ context.Context, chapter *domain.Chapter) error {
-	c := t.Collector.Clone()
+func (t *tcbscans) GetImageURLs(ctx context.Context, chapter *domain.Chapter) error {
+	c := newCollector(ctx)
 
 	var imageInfos []domain.ImageInfo
 
@@ -141,13 +199,13 @@ func (t *tcbscans) GetImageURLs(_ context.Context, chapter *domain.Chapter) erro
 		return err
 	}
 
-	err = c.Visit(path)
+	err = visit(ctx, c, path)
 	if err != nil {
 		return err
 	}
 
 	if len(imageInfos) == 0 {
-		return fmt.Errorf("failed to get image urls for chapter number: %g", chapter.Number)
+		return fmt.Errorf("failed to get image urls for chapter number: %s", chapter.Number)
 	}
 
 	chapter.ImageInfo = imageInfos
@@ -155,19 +213,12 @@ func (t *tcbscans) GetImageURLs(_ context.Context, chapter *domain.Chapter) erro
 }
 
 // getChapterNumber gets the chapter number from the scraped chapter name
-func (t *tcbscans) getChapterNumber(name string) (float32, error) {
-	var number float64
-	var err error
-
+func (t *tcbscans) getChapterNumber(name string) (domain.ChapterNumber, error) {
 	// FindSubmatch returns an array where the first element is the full match, and the rest are submatches.
 	matches := chapterNumberPattern.FindStringSubmatch(name)
 	if len(matches) > 1 {
-		number, err = strconv.ParseFloat(matches[1], 32)
-		if err != nil {
-			return 0, err
-		}
-		return float32(number), nil
+		return domain.ParseChapterNumber(matches[1]), nil
 	}
 
-	return 0, err
+	return domain.ChapterNumber{}, fmt.Errorf("failed to get chapter number from: %s", name)
 }
@@ -0,0 +1,39 @@
+package source
+
+import "testing"
+
+func TestTCBScansGetChapterNumber(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{name: "One Piece Chapter 1100", want: "1100"},
+		{name: "One Piece Chapter 1100:", want: "1100"},
+		{name: "One Piece Chapter 1100, The Gathering", want: "1100"},
+		{name: "Jujutsu Kaisen Chapter 236.5", want: "236.5"},
+		{name: "One Piece Chapter 1053a", want: "1053a"},
+		{name: "One Piece Chapter 1053a: Extra", want: "1053a"},
+		{name: "One Piece Chapter 1053abc", want: "1053"},
+		{name: "One Piece Chapter One", wantErr: true},
+		{name: "Chainsaw Man", wantErr: true},
+	}
+
+	s := &tcbscans{}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.getChapterNumber(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if !got.IsNumeric() || got.String() != tt.want {
+				t.Errorf("chapter = %q (numeric %v), want %q", got.String(), got.IsNumeric(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,444 @@
+package source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"mangarr/internal/domain"
+	"mangarr/internal/sanitize"
+
+	"github.com/gocolly/colly"
+)
+
+// supported WordPress manga themes
+const (
+	ThemeMangaStream = "mangastream"
+	ThemeMadara      = "madara"
+)
+
+// themePreset holds the defaults of a theme
+type themePreset struct {
+	selectors  domain.ThemeSelectors
+	dateLayout string
+	// ajaxChapters is set if the theme may load the chapter list separately from the manga page
+	ajaxChapters bool
+}
+
+var themePresets = map[string]themePreset{
+	ThemeMangaStream: {
+		selectors: domain.ThemeSelectors{
+			Title:             ".entry-title",
+			Cover:             ".thumb img",
+			Synopsis:          ".entry-content[itemprop='description']",
+			AltTitles:         ".alternative",
+			Genres:            ".mgen a",
+			Status:            ".imptdt:contains('Status') i",
+			Authors:           ".fmed:contains('Author') span",
+			Artists:           ".fmed:contains('Artist') span",
+			Chapters:          ".eplister li",
+			ChapterURL:        "a",
+			ChapterNumberAttr: "data-num",
+			ChapterDate:       ".chapterdate",
+			Images:            "#readerarea img",
+		},
+		dateLayout: "January 2, 2006",
+	},
+	ThemeMadara: {
+		selectors: domain.ThemeSelectors{
+			Title:         ".post-title h1",
+			Cover:         ".summary_image img",
+			Synopsis:      ".description-summary .summary__content",
+			AltTitles:     ".post-content_item:contains('Alternative') .summary-content",
+			Genres:        ".genres-content a",
+			Status:        ".post-content_item:contains('Status') .summary-content",
+			Authors:       ".author-content a",
+			Artists:       ".artist-content a",
+			Chapters:      "li.wp-manga-chapter",
+			ChapterURL:    "a",
+			ChapterNumber: "a",
+			ChapterDate:   ".chapter-release-date",
+			Images:        ".reading-content img",
+		},
+		dateLayout:   "January 2, 2006",
+		ajaxChapters: true,
+	},
+}
+
+// lazy loading themes keep the actual image url in one of these attributes
+var imageAttrs = []string{"data-src", "data-lazy-src", "data-cfsrc", "src"}
+
+// themeConfig is a validated ThemeSource with the defaults of its theme applied
+type themeConfig struct {
+	key          string
+	def          *domain.ThemeSource
+	selectors    domain.ThemeSelectors
+	dateLayout   string
+	ajaxChapters bool
+}
+
+type theme struct {
+	MangaURL string
+	config   *themeConfig
+}
+
+// RegisterThemes validates the theme source definitions from the config and registers the valid ones as sources
+func RegisterThemes(defs map[string]*domain.ThemeSource) error {
+	var errs []error
+
+	for key, def := range defs {
+		if err := registerTheme(key, def); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func registerTheme(key string, def *domain.ThemeSource) error {
+	if _, ok := Lookup(key); ok {
+		return fmt.Errorf("source %q is already registered", key)
+	}
+
+	cfg, err := newThemeConfig(key, def)
+	if err != nil {
+		return fmt.Errorf("invalid source %q: %w", key, err)
+	}
+
+	var languages []string
+	if len(def.Language) != 0 {
+		languages = []string{def.Language}
+	}
+
+	Register(Definition{
+		Key:  key,
+		Name: cfg.name(),
+		Fields: []Field{
+			{
+				Name:     InputManga,
+				Required: true,
+				Help:     fmt.Sprintf("URL of the series on %s, must start with %s", cfg.name(), def.BaseURL),
+			},
+		},
+		Languages: languages,
+		Manhwa:    def.Manhwa,
+		New: func(in Input) domain.Source {
+			return &theme{
+				MangaURL: in.Manga,
+				config:   cfg,
+			}
+		},
+	})
+
+	return nil
+}
+
+func newThemeConfig(key string, def *domain.ThemeSource) (*themeConfig, error) {
+	if def == nil {
+		return nil, fmt.Errorf("definition is empty")
+	}
+
+	preset, ok := themePresets[strings.ToLower(def.Theme)]
+	if !ok {
+		return nil, fmt.Errorf("unknown theme %q, must be one of: %s, %s", def.Theme, ThemeMangaStream, ThemeMadara)
+	}
+
+	if len(def.BaseURL) == 0 {
+		return nil, fmt.Errorf("base url is required")
+	}
+
+	if _, err := url.Parse(def.BaseURL); err != nil {
+		return nil, fmt.Errorf("invalid base url: %w", err)
+	}
+
+	cfg := &themeConfig{
+		key:          key,
+		def:          def,
+		selectors:    preset.selectors,
+		dateLayout:   preset.dateLayout,
+		ajaxChapters: preset.ajaxChapters,
+	}
+
+	if len(def.DateLayout) != 0 {
+		cfg.dateLayout = def.DateLayout
+	}
+
+	s := &cfg.selectors
+	o := def.Selectors
+
+	overrides := []struct {
+		target   *string
+		override string
+	}{
+		{&s.Title, o.Title},
+		{&s.Cover, o.Cover},
+		{&s.Synopsis, o.Synopsis},
+		{&s.AltTitles, o.AltTitles},
+		{&s.Genres, o.Genres},
+		{&s.Status, o.Status},
+		{&s.Authors, o.Authors},
+		{&s.Artists, o.Artists},
+		{&s.Chapters, o.Chapters},
+		{&s.ChapterURL, o.ChapterURL},
+		{&s.ChapterNumber, o.ChapterNumber},
+		{&s.ChapterNumberAttr, o.ChapterNumberAttr},
+		{&s.ChapterDate, o.ChapterDate},
+		{&s.Images, o.Images},
+		{&s.ImageAttr, o.ImageAttr},
+	}
+
+	for _, o := range overrides {
+		if len(o.override) != 0 {
+			*o.target = o.override
+		}
+	}
+
+	return cfg, nil
+}
+
+func (c *themeConfig) name() string {
+	if len(c.def.Name) != 0 {
+		return c.def.Name
+	}
+
+	return c.key
+}
+
+func (t *theme) String() string {
+	return t.config.name()
+}
+
+func (t *theme) ValidateInput() error {
+	if !strings.HasPrefix(t.MangaURL, t.config.def.BaseURL) {
+		return fmt.Errorf("the url for %s must start with %s", t, t.config.def.BaseURL)
+	}
+
+	if _, err := url.Parse(t.MangaURL); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *theme) GetManga(ctx context.Context) (domain.Manga, error) {
+	def := t.config.def
+	sel := t.config.selectors
+
+	var manga domain.Manga
+	manga.Chapters = make(map[string][]domain.Chapter)
+
+	manga.Metadata = domain.Metadata{
+		SourceURL: t.MangaURL,
+	}
+
+	if def.Manhwa {
+		manga.Metadata.ReadingDirection = domain.DirectionVertical
+	}
+
+	c := newCollector(ctx)
+
+	c.OnHTML(sel.Title, func(e *colly.HTMLElement) {
+		if len(manga.Title) == 0 {
+			manga.Title = sanitize.Filename(e.Text)
+		}
+	})
+
+	c.OnHTML(sel.Cover, func(e *colly.HTMLElement) {
+		if len(manga.Metadata.CoverURL) == 0 {
+			// the image attribute only applies to the images of chapters
+			manga.Metadata.CoverURL = e.Request.AbsoluteURL(imageURL(e, ""))
+		}
+	})
+
+	c.OnHTML(sel.Synopsis, func(e *colly.HTMLElement) {
+		manga.Metadata.Synopsis = strings.TrimSpace(e.Text)
+	})
+
+	c.OnHTML(sel.AltTitles, func(e *colly.HTMLElement) {
+		for _, altTitle := range strings.Split(e.Text, ",") {
+			if altTitle = strings.TrimSpace(altTitle); len(altTitle) != 0 {
+				manga.Metadata.AltTitles = append(manga.Metadata.AltTitles, altTitle)
+			}
+		}
+	})
+
+	c.OnHTML(sel.Genres, func(e *colly.HTMLElement) {
+		manga.Metadata.Genres = appendValue(manga.Metadata.Genres, e.Text)
+	})
+
+	c.OnHTML(sel.Status, func(e *colly.HTMLElement) {
+		manga.Metadata.Status = domain.ParsePublicationStatus(strings.TrimSpace(e.Text))
+	})
+
+	c.OnHTML(sel.Authors, func(e *colly.HTMLElement) {
+		manga.Metadata.Authors = appendValue(manga.Metadata.Authors, e.Text)
+	})
+
+	c.OnHTML(sel.Artists, func(e *colly.HTMLElement) {
+		manga.Metadata.Artists = appendValue(manga.Metadata.Artists, e.Text)
+	})
+
+	// the id is needed to request the chapter list of older madara sites
+	var mangaID string
+	c.OnHTML("#manga-chapters-holder", func(e *colly.HTMLElement) {
+		mangaID = e.Attr("data-id")
+	})
+
+	t.onChapter(c, manga)
+
+	err := visit(ctx, c, t.MangaURL)
+	if err != nil {
+		return domain.Manga{}, err
+	}
+
+	if len(manga.Title) == 0 {
+		return domain.Manga{}, fmt.Errorf("failed to get manga for provided url: %s", t.MangaURL)
+	}
+
+	if len(manga.Chapters) == 0 && t.config.ajaxChapters {
+		if err := t.getAjaxChapters(ctx, manga, mangaID); err != nil {
+			return domain.Manga{}, err
+		}
+	}
+
+	if len(manga.Chapters) == 0 {
+		return domain.Manga{}, fmt.Errorf("failed to get chapters for manga: %s", manga.Title)
+	}
+
+	return manga, nil
+}
+
+// getAjaxChapters requests the chapter list that newer madara sites load from the manga url and older ones from
+// the admin ajax endpoint
+func (t *theme) getAjaxChapters(ctx context.Context, manga domain.Manga, mangaID string) error {
+	c := newCollector(ctx)
+	t.onChapter(c, manga)
+
+	err := post(ctx, c, strings.TrimSuffix(t.MangaURL, "/")+"/ajax/chapters/", nil)
+	if err == nil && len(manga.Chapters) != 0 {
+		return nil
+	}
+
+	if ctxErr := ctx.Err(); ctxErr != nil || len(mangaID) == 0 {
+		return ctxErr
+	}
+
+	adminURL, err := url.JoinPath(t.config.def.BaseURL, "wp-admin", "admin-ajax.php")
+	if err != nil {
+		return err
+	}
+
+	return post(ctx, c, adminURL, map[string]string{
+		"action": "manga_get_chapters",
+		"manga":  mangaID,
+	})
+}
+
+// onChapter adds every chapter found by the collector to manga
+func (t *theme) onChapter(c *colly.Collector, manga domain.Manga) {
+	def := t.config.def
+	sel := t.config.selectors
+
+	c.OnHTML(sel.Chapters, func(e *colly.HTMLElement) {
+		var label string
+		if len(sel.ChapterNumberAttr) != 0 {
+			label = strings.TrimSpace(e.Attr(sel.ChapterNumberAttr))
+		} else {
+			text := e.Text
+			if len(sel.ChapterNumber) != 0 {
+				text = e.ChildText(sel.ChapterNumber)
+			}
+			label = defaultChapterNumberPattern.FindString(strings.ToLower(text))
+		}
+
+		if len(label) == 0 {
+			return
+		}
+
+		chapterURL := e.ChildAttr(sel.ChapterURL, "href")
+		if len(chapterURL) == 0 {
+			return
+		}
+
+		var publishedAt string
+		if len(sel.ChapterDate) != 0 {
+			publishedAt = e.ChildText(sel.ChapterDate)
+		}
+
+		manga.AddChapter(domain.Chapter{
+			URL:         e.Request.AbsoluteURL(chapterURL),
+			Number:      domain.ParseChapterNumber(label),
+			PublishedAt: parseReleaseDate(publishedAt, t.config.dateLayout),
+			Group:       t.String(),
+			Language:    def.Language,
+			IsManhwa:    def.Manhwa,
+		})
+	})
+}
+
+func (t *theme) GetChapters(_ context.Context, _ domain.Manga) error {
+	return nil
+}
+
+func (t *theme) GetImageURLs(ctx context.Context, chapter *domain.Chapter) error {
+	prefixes := t.config.def.ImagePrefixes
+
+	c := newCollector(ctx)
+
+	var imageInfos []domain.ImageInfo
+
+	c.OnHTML(t.config.selectors.Images, func(e *colly.HTMLElement) {
+		imgURL := imageURL(e, t.config.selectors.ImageAttr)
+		if len(imgURL) == 0 {
+			return
+		}
+
+		imgURL = e.Request.AbsoluteURL(imgURL)
+
+		if len(prefixes) != 0 && !hasAnyPrefix(imgURL, prefixes) {
+			return
+		}
+
+		imageInfos = append(imageInfos, domain.ImageInfo{ImageURL: imgURL})
+	})
+
+	err := visit(ctx, c, chapter.URL)
+	if err != nil {
+		return err
+	}
+
+	if len(imageInfos) == 0 {
+		return fmt.Errorf("failed to get image urls for chapter number: %s", chapter.Number)
+	}
+
+	chapter.ImageInfo = imageInfos
+	return nil
+}
+
+// imageURL returns the value of attr of the image element, or the first of the attributes lazy loaded images
+// keep their url in if attr is empty
+func imageURL(e *colly.HTMLElement, attr string) string {
+	if len(attr) != 0 {
+		return strings.TrimSpace(e.Attr(attr))
+	}
+
+	for _, attr := range imageAttrs {
+		if value := strings.TrimSpace(e.Attr(attr)); len(value) != 0 {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// appendValue appends the trimmed value to values, empty values and placeholders are skipped
+func appendValue(values []string, value string) []string {
+	value = strings.TrimSpace(value)
+	if len(value) == 0 || value == "-" {
+		return values
+	}
+
+	return append(values, value)
+}
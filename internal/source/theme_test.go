@@ -0,0 +1,186 @@
+package source
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"mangarr/internal/domain"
+)
+
+func newTestTheme(t *testing.T, mangaURL string, def *domain.ThemeSource) *theme {
+	t.Helper()
+
+	cfg, err := newThemeConfig("mysite", def)
+	if err != nil {
+		t.Fatalf("newThemeConfig: %v", err)
+	}
+
+	return &theme{MangaURL: mangaURL, config: cfg}
+}
+
+func TestThemeMangaStreamGetManga(t *testing.T) {
+	server := httptest.NewServer(http.FileServer(http.Dir(filepath.Join("testdata", "theme", "mangastream"))))
+	defer server.Close()
+
+	s := newTestTheme(t, server.URL+"/series.html", &domain.ThemeSource{
+		Theme:    ThemeMangaStream,
+		Name:     "My Site",
+		BaseURL:  server.URL,
+		Language: "en",
+		// the attribute of the reader images mustn't be used for the cover
+		Selectors: domain.ThemeSelectors{ImageAttr: "data-src"},
+	})
+
+	manga, err := s.GetManga(context.Background())
+	if err != nil {
+		t.Fatalf("GetManga: %v", err)
+	}
+
+	if manga.Title != "Solo Leveling" {
+		t.Errorf("Title = %q, want %q", manga.Title, "Solo Leveling")
+	}
+
+	m := manga.Metadata
+	if m.CoverURL != server.URL+"/covers/solo-leveling.jpg" {
+		t.Errorf("CoverURL = %q", m.CoverURL)
+	}
+	if m.Synopsis != "The weakest hunter of all mankind." {
+		t.Errorf("Synopsis = %q", m.Synopsis)
+	}
+	if want := []string{"Na Honjaman Level-Up", "Only I Level Up"}; !slices.Equal(m.AltTitles, want) {
+		t.Errorf("AltTitles = %v, want %v", m.AltTitles, want)
+	}
+	if want := []string{"Action", "Fantasy"}; !slices.Equal(m.Genres, want) {
+		t.Errorf("Genres = %v, want %v", m.Genres, want)
+	}
+	if m.Status != domain.StatusCompleted {
+		t.Errorf("Status = %q, want %q", m.Status, domain.StatusCompleted)
+	}
+	if !slices.Equal(m.Authors, []string{"Chugong"}) || !slices.Equal(m.Artists, []string{"DUBU"}) {
+		t.Errorf("Authors = %v, Artists = %v", m.Authors, m.Artists)
+	}
+
+	want := map[string]time.Time{
+		"1": time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+		"2": time.Date(2024, time.March, 8, 0, 0, 0, 0, time.UTC),
+	}
+
+	if len(manga.Chapters) != len(want) {
+		t.Errorf("chapters = %d, want %d", len(manga.Chapters), len(want))
+	}
+
+	for key, publishedAt := range want {
+		releases := manga.Chapters[key]
+		if len(releases) != 1 {
+			t.Errorf("releases of chapter %s = %d, want 1", key, len(releases))
+			continue
+		}
+
+		chapter := releases[0]
+		if chapter.URL != server.URL+"/chapter.html" {
+			t.Errorf("chapter %s: URL = %q", key, chapter.URL)
+		}
+		if !chapter.PublishedAt.Equal(publishedAt) {
+			t.Errorf("chapter %s: PublishedAt = %s, want %s", key, chapter.PublishedAt, publishedAt)
+		}
+		if chapter.Group != "My Site" || chapter.Language != "en" {
+			t.Errorf("chapter %s: Group = %q, Language = %q", key, chapter.Group, chapter.Language)
+		}
+	}
+}
+
+func TestThemeMadaraAjaxChapters(t *testing.T) {
+	server := httptest.NewServer(http.FileServer(http.Dir(filepath.Join("testdata", "theme", "madara"))))
+	defer server.Close()
+
+	s := newTestTheme(t, server.URL+"/series/", &domain.ThemeSource{
+		Theme:   ThemeMadara,
+		BaseURL: server.URL,
+	})
+
+	manga, err := s.GetManga(context.Background())
+	if err != nil {
+		t.Fatalf("GetManga: %v", err)
+	}
+
+	if manga.Title != "Omniscient Reader" {
+		t.Errorf("Title = %q, want %q", manga.Title, "Omniscient Reader")
+	}
+	if manga.Metadata.CoverURL != server.URL+"/covers/omniscient-reader.jpg" {
+		t.Errorf("CoverURL = %q", manga.Metadata.CoverURL)
+	}
+	if manga.Metadata.Status != domain.StatusOngoing {
+		t.Errorf("Status = %q, want %q", manga.Metadata.Status, domain.StatusOngoing)
+	}
+
+	// the chapters are only listed by the ajax endpoint
+	want := map[string]string{
+		"10":  server.URL + "/series/chapter-10/",
+		"9.5": server.URL + "/series/chapter-9-5/",
+	}
+
+	if len(manga.Chapters) != len(want) {
+		t.Errorf("chapters = %d, want %d", len(manga.Chapters), len(want))
+	}
+
+	for key, chapterURL := range want {
+		if releases := manga.Chapters[key]; len(releases) != 1 || releases[0].URL != chapterURL {
+			t.Errorf("releases of chapter %s = %+v, want one at %s", key, releases, chapterURL)
+		}
+	}
+}
+
+func TestThemeGetImageURLs(t *testing.T) {
+	server := httptest.NewServer(http.FileServer(http.Dir(filepath.Join("testdata", "theme", "mangastream"))))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		imageAttr string
+		prefixes  []string
+		want      []string
+	}{
+		{
+			name:     "lazy loaded images",
+			prefixes: []string{server.URL, "https://cdn.example.org"},
+			want: []string{
+				server.URL + "/images/001.jpg",
+				"https://cdn.example.org/images/002.jpg",
+				server.URL + "/images/003.jpg",
+			},
+		},
+		{
+			name:      "image attribute",
+			imageAttr: "data-src",
+			want: []string{
+				server.URL + "/images/001.jpg",
+				"https://ads.example.org/banner.jpg",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestTheme(t, server.URL+"/series.html", &domain.ThemeSource{
+				Theme:         ThemeMangaStream,
+				BaseURL:       server.URL,
+				ImagePrefixes: tt.prefixes,
+				Selectors:     domain.ThemeSelectors{ImageAttr: tt.imageAttr},
+			})
+
+			chapter := domain.Chapter{URL: server.URL + "/chapter.html", Number: domain.ParseChapterNumber("1")}
+			if err := s.GetImageURLs(context.Background(), &chapter); err != nil {
+				t.Fatalf("GetImageURLs: %v", err)
+			}
+
+			if got := imageInfoURLs(chapter.ImageInfo); !slices.Equal(got, tt.want) {
+				t.Errorf("images = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
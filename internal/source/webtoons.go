@@ -0,0 +1,262 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"mangarr/internal/domain"
+	"mangarr/internal/sanitize"
+
+	"github.com/gocolly/colly"
+)
+
+const (
+	webtoonsURL = "https://www.webtoons.com"
+	// webtoonsMaxPages guards against paging forever if the site keeps returning new episodes
+	webtoonsMaxPages = 500
+)
+
+var webtoonsTitleNo = regexp.MustCompile(`^\d+$`)
+
+// the image server rejects requests that don't come from the site
+var webtoonsHeaders = map[string]string{
+	"Referer": webtoonsURL + "/",
+}
+
+type webtoons struct {
+	MangaURL string
+}
+
+func init() {
+	Register(Definition{
+		Key:  "webtoons",
+		Name: "WEBTOON",
+		Fields: []Field{
+			{Name: InputManga, Required: true, Help: "URL of the series on WEBTOON or its title_no", Example: "https://www.webtoons.com/en/fantasy/tower-of-god/list?title_no=95"},
+		},
+		Manhwa: true,
+		New: func(in Input) domain.Source {
+			return NewWebtoons(in.Manga)
+		},
+	})
+}
+
+func NewWebtoons(manga string) domain.Source {
+	return &webtoons{
+		MangaURL: manga,
+	}
+}
+
+func (w *webtoons) String() string {
+	return "WEBTOON"
+}
+
+func (w *webtoons) ValidateInput() error {
+	if len(w.MangaURL) == 0 {
+		return fmt.Errorf("webtoons url or title_no is required")
+	}
+
+	// the episode list of a bare title_no redirects to the series
+	if webtoonsTitleNo.MatchString(w.MangaURL) {
+		w.MangaURL = webtoonsURL + "/episodeList?titleNo=" + w.MangaURL
+		return nil
+	}
+
+	u, err := url.Parse(w.MangaURL)
+	if err != nil {
+		return err
+	}
+
+	if host := u.Hostname(); host != "webtoons.com" && !strings.HasSuffix(host, ".webtoons.com") {
+		return fmt.Errorf("the url for webtoons must be on webtoons.com")
+	}
+
+	if len(u.Query().Get("title_no")) == 0 {
+		return fmt.Errorf("the url for webtoons must contain the title_no of the series")
+	}
+
+	return nil
+}
+
+func (w *webtoons) GetManga(ctx context.Context) (domain.Manga, error) {
+	var manga domain.Manga
+	manga.Chapters = make(map[string][]domain.Chapter)
+
+	manga.Metadata = domain.Metadata{
+		ReadingDirection: domain.DirectionVertical,
+		SourceURL:        w.MangaURL,
+	}
+
+	// the language is the first segment of the series url, which is only known after redirects
+	var listURL *url.URL
+	var language string
+
+	c := newCollector(ctx)
+	w.onRequest(c)
+
+	c.OnResponse(func(r *colly.Response) {
+		listURL = r.Request.URL
+		language = webtoonsLanguage(r.Request.URL)
+	})
+
+	c.OnHTML(".info .subj", func(e *colly.HTMLElement) {
+		if len(manga.Title) == 0 {
+			manga.Title = sanitize.Filename(e.Text)
+		}
+	})
+
+	c.OnHTML("meta[property='og:image']", func(e *colly.HTMLElement) {
+		manga.Metadata.CoverURL = e.Attr("content")
+	})
+
+	c.OnHTML("p.summary", func(e *colly.HTMLElement) {
+		manga.Metadata.Synopsis = strings.TrimSpace(e.Text)
+	})
+
+	c.OnHTML(".info .genre", func(e *colly.HTMLElement) {
+		manga.Metadata.Genres = appendValue(manga.Metadata.Genres, e.Text)
+	})
+
+	c.OnHTML(".author_area", func(e *colly.HTMLElement) {
+		// the author area also holds the button to view the author's profile
+		author := strings.TrimSpace(e.DOM.Clone().Children().Remove().End().Text())
+		if len(author) == 0 {
+			author = e.ChildText("a")
+		}
+
+		for _, name := range strings.Split(author, ",") {
+			manga.Metadata.Authors = appendValue(manga.Metadata.Authors, name)
+		}
+	})
+
+	c.OnHTML(".day_info", func(e *colly.HTMLElement) {
+		if strings.Contains(strings.ToUpper(e.Text), "COMPLETED") {
+			manga.Metadata.Status = domain.StatusCompleted
+		} else {
+			manga.Metadata.Status = domain.StatusOngoing
+		}
+	})
+
+	var found int
+	addEpisode := func(e *colly.HTMLElement) {
+		chapterURL := e.ChildAttr("a", "href")
+		if len(chapterURL) == 0 {
+			return
+		}
+
+		label := strings.TrimPrefix(strings.TrimSpace(e.ChildText(".tx")), "#")
+		if len(label) == 0 {
+			label = e.Attr("data-episode-no")
+		}
+
+		number := domain.ParseChapterNumber(label)
+		if len(manga.Chapters[number.Key()]) != 0 {
+			return
+		}
+
+		found++
+
+		manga.AddChapter(domain.Chapter{
+			ID:          e.Attr("data-episode-no"),
+			URL:         e.Request.AbsoluteURL(chapterURL),
+			Number:      number,
+			Title:       sanitize.Filename(e.ChildText(".subj span")),
+			PublishedAt: parseReleaseDate(e.ChildText(".date"), "Jan 2, 2006"),
+			Group:       "WEBTOON",
+			Language:    language,
+			IsManhwa:    true,
+			Headers:     webtoonsHeaders,
+		})
+	}
+
+	c.OnHTML("#_listUl li", addEpisode)
+
+	err := visit(ctx, c, w.MangaURL)
+	if err != nil {
+		return domain.Manga{}, err
+	}
+
+	if len(manga.Title) == 0 || listURL == nil {
+		return domain.Manga{}, fmt.Errorf("failed to get manga for provided url: %s", w.MangaURL)
+	}
+
+	// the following pages only add episodes
+	pc := newCollector(ctx)
+	w.onRequest(pc)
+	pc.OnHTML("#_listUl li", addEpisode)
+
+	// pages past the last one show the last page again, so paging stops once a page has no new episodes
+	for page := 2; found != 0 && page <= webtoonsMaxPages; page++ {
+		found = 0
+
+		pageURL := *listURL
+		query := pageURL.Query()
+		query.Set("page", strconv.Itoa(page))
+		pageURL.RawQuery = query.Encode()
+
+		if err := visit(ctx, pc, pageURL.String()); err != nil {
+			return domain.Manga{}, err
+		}
+	}
+
+	if len(manga.Chapters) == 0 {
+		return domain.Manga{}, fmt.Errorf("failed to get chapters for manga: %s", manga.Title)
+	}
+
+	return manga, nil
+}
+
+func (w *webtoons) GetChapters(_ context.Context, _ domain.Manga) error {
+	return nil
+}
+
+func (w *webtoons) GetImageURLs(ctx context.Context, chapter *domain.Chapter) error {
+	c := newCollector(ctx)
+	w.onRequest(c)
+
+	var imageInfos []domain.ImageInfo
+
+	c.OnHTML("#_imageList img", func(e *colly.HTMLElement) {
+		imgURL := strings.TrimSpace(e.Attr("data-url"))
+		if len(imgURL) == 0 {
+			return
+		}
+
+		imageInfos = append(imageInfos, domain.ImageInfo{ImageURL: imgURL})
+	})
+
+	err := visit(ctx, c, chapter.URL)
+	if err != nil {
+		return err
+	}
+
+	if len(imageInfos) == 0 {
+		return fmt.Errorf("failed to get image urls for chapter number: %s", chapter.Number)
+	}
+
+	chapter.ImageInfo = imageInfos
+	chapter.Headers = webtoonsHeaders
+	return nil
+}
+
+// onRequest passes the age gate and consent checks that would otherwise hide the episodes
+func (w *webtoons) onRequest(c *colly.Collector) {
+	c.OnRequest(func(r *colly.Request) {
+		r.Headers.Set("Referer", webtoonsURL+"/")
+		r.Headers.Set("Cookie", "ageGatePass=true; needGDPR=false; needCCPA=false; needCOPPA=false")
+	})
+}
+
+// webtoonsLanguage returns the language of a series url, e.g. "en" for https://www.webtoons.com/en/...
+func webtoonsLanguage(u *url.URL) string {
+	segment, _, _ := strings.Cut(strings.TrimPrefix(u.Path, "/"), "/")
+	if len(segment) == 0 || strings.Contains(segment, ".") || segment == "episodeList" {
+		return ""
+	}
+
+	return segment
+}
@@ -0,0 +1,154 @@
+package source
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"mangarr/internal/domain"
+)
+
+func TestWebtoonsValidateInput(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantErr bool
+	}{
+		{input: "https://www.webtoons.com/en/fantasy/tower-of-god/list?title_no=95"},
+		{input: "https://webtoons.com/en/fantasy/tower-of-god/list?title_no=95"},
+		{input: "95"},
+		{input: "https://evilwebtoons.com/en/fantasy/tower-of-god/list?title_no=95", wantErr: true},
+		{input: "https://www.webtoons.com.evil.org/en/fantasy/tower-of-god/list?title_no=95", wantErr: true},
+		{input: "https://www.webtoons.com/en/fantasy/tower-of-god/list", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			w := &webtoons{MangaURL: tt.input}
+			if err := w.ValidateInput(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateInput() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// webtoonsTestServer serves the episode list of Tower of God, pages past the last one show the last page again
+func webtoonsTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// episodes are only listed if the age gate was passed
+		if !strings.Contains(r.Header.Get("Cookie"), "ageGatePass=true") {
+			http.Error(w, "age gate", http.StatusForbidden)
+			return
+		}
+
+		name := "viewer.html"
+		if strings.HasSuffix(r.URL.Path, "/list") {
+			name = "list.html"
+			if page, _ := strconv.Atoi(r.URL.Query().Get("page")); page > 1 {
+				name = "list_page2.html"
+			}
+		}
+
+		http.ServeFile(w, r, filepath.Join("testdata", "webtoons", name))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestWebtoonsGetManga(t *testing.T) {
+	server := webtoonsTestServer(t)
+
+	w := &webtoons{MangaURL: server.URL + "/en/fantasy/tower-of-god/list?title_no=95"}
+
+	manga, err := w.GetManga(context.Background())
+	if err != nil {
+		t.Fatalf("GetManga: %v", err)
+	}
+
+	if manga.Title != "Tower of God" {
+		t.Errorf("Title = %q, want %q", manga.Title, "Tower of God")
+	}
+
+	m := manga.Metadata
+	if m.CoverURL != "https://swebtoon-phinf.pstatic.net/tower-of-god/cover.jpg" {
+		t.Errorf("CoverURL = %q", m.CoverURL)
+	}
+	if !slices.Equal(m.Authors, []string{"SIU"}) {
+		t.Errorf("Authors = %v, want [SIU]", m.Authors)
+	}
+	if !slices.Equal(m.Genres, []string{"Fantasy"}) {
+		t.Errorf("Genres = %v, want [Fantasy]", m.Genres)
+	}
+	if m.Status != domain.StatusOngoing {
+		t.Errorf("Status = %q, want %q", m.Status, domain.StatusOngoing)
+	}
+
+	want := map[string]string{
+		"1": "[Season 1] Prologue",
+		"2": "[Season 1] Ep. 1",
+		"3": "[Season 1] Ep. 2",
+		"4": "[Season 1] Ep. 3",
+	}
+
+	if len(manga.Chapters) != len(want) {
+		t.Errorf("chapters = %d, want %d", len(manga.Chapters), len(want))
+	}
+
+	for key, title := range want {
+		releases := manga.Chapters[key]
+		if len(releases) != 1 {
+			t.Errorf("releases of chapter %s = %d, want 1", key, len(releases))
+			continue
+		}
+
+		chapter := releases[0]
+		if chapter.Title != title {
+			t.Errorf("chapter %s: Title = %q, want %q", key, chapter.Title, title)
+		}
+		if chapter.ID != key {
+			t.Errorf("chapter %s: ID = %q", key, chapter.ID)
+		}
+		if chapter.Language != "en" {
+			t.Errorf("chapter %s: Language = %q, want en", key, chapter.Language)
+		}
+		if chapter.Headers["Referer"] != webtoonsURL+"/" {
+			t.Errorf("chapter %s: Headers = %v", key, chapter.Headers)
+		}
+	}
+
+	if published := manga.Chapters["4"][0].PublishedAt; !published.Equal(time.Date(2010, time.July, 14, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("chapter 4: PublishedAt = %s", published)
+	}
+}
+
+func TestWebtoonsGetImageURLs(t *testing.T) {
+	server := webtoonsTestServer(t)
+
+	w := &webtoons{}
+	chapter := domain.Chapter{URL: server.URL + "/en/fantasy/tower-of-god/season-1-ep-3/viewer?title_no=95&episode_no=4"}
+
+	if err := w.GetImageURLs(context.Background(), &chapter); err != nil {
+		t.Fatalf("GetImageURLs: %v", err)
+	}
+
+	want := []string{
+		"https://webtoon-phinf.pstatic.net/95/4/001.jpg?type=q90",
+		"https://webtoon-phinf.pstatic.net/95/4/002.jpg?type=q90",
+	}
+	if got := imageInfoURLs(chapter.ImageInfo); !slices.Equal(got, want) {
+		t.Errorf("images = %v, want %v", got, want)
+	}
+
+	// the image server rejects requests without the referer of the site
+	if chapter.Headers["Referer"] != webtoonsURL+"/" {
+		t.Errorf("Headers = %v", chapter.Headers)
+	}
+}
@@ -1,10 +1,10 @@
 package templater
 
 import (
-	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"mangarr/internal/domain"
 	"mangarr/internal/utils"
@@ -25,12 +25,13 @@ func New(manga domain.Manga, chapter domain.Chapter) *Templater {
 }
 
 func (t *Templater) handleNum(options string) string {
-	if options == "" {
-		return fmt.Sprintf("%g", t.Chapter.Number)
+	// labels without a numeric part like "Extra" can't be padded
+	if options == "" || !t.Chapter.Number.IsNumeric() {
+		return t.Chapter.Number.String()
 	}
 
 	length, _ := strconv.ParseInt(strings.ReplaceAll(options, ":", ""), 10, 32)
-	return utils.PadFloat(t.Chapter.Number, int(length))
+	return t.Chapter.Number.Pad(int(length))
 }
 
 func (t *Templater) handleMangaTitle(options string) string {
@@ -51,6 +52,69 @@ func (t *Templater) handleChapterTitle(options string) string {
 	return strings.ReplaceAll(cleanString, "<.>", t.Chapter.Title)
 }
 
+func (t *Templater) handleVolume(options string) string {
+	if t.Chapter.Volume == "" {
+		return ""
+	}
+
+	cleanString := strings.ReplaceAll(options, ":", "")
+	if strings.Contains(cleanString, "<.>") {
+		return strings.ReplaceAll(cleanString, "<.>", t.Chapter.Volume)
+	}
+
+	length, err := strconv.ParseInt(cleanString, 10, 32)
+	if err != nil {
+		return t.Chapter.Volume
+	}
+
+	volume, err := strconv.ParseFloat(t.Chapter.Volume, 64)
+	if err != nil {
+		return t.Chapter.Volume
+	}
+
+	return utils.PadFloat(volume, int(length))
+}
+
+func (t *Templater) handleGroup(options string) string {
+	if t.Chapter.Group == "" {
+		return ""
+	}
+
+	if options == "" {
+		return t.Chapter.Group
+	}
+
+	cleanString := strings.ReplaceAll(options, ":", "")
+	return strings.ReplaceAll(cleanString, "<.>", t.Chapter.Group)
+}
+
+func (t *Templater) handleLanguage(options string) string {
+	if t.Chapter.Language == "" {
+		return ""
+	}
+
+	if options == "" {
+		return t.Chapter.Language
+	}
+
+	cleanString := strings.ReplaceAll(options, ":", "")
+	return strings.ReplaceAll(cleanString, "<.>", t.Chapter.Language)
+}
+
+// handleDate formats the release date with the go time layout given in options, colons are kept as they are part of layouts
+func (t *Templater) handleDate(options string) string {
+	if t.Chapter.PublishedAt.IsZero() {
+		return ""
+	}
+
+	layout := strings.TrimPrefix(options, ":")
+	if layout == "" {
+		layout = time.DateOnly
+	}
+
+	return t.Chapter.PublishedAt.Format(layout)
+}
+
 func (t *Templater) ExecTemplate(template string) string {
 	newString := template
 	for _, match := range templatePattern.FindAllStringSubmatch(template, -1) {
@@ -68,6 +132,14 @@ func (t *Templater) ExecTemplate(template string) string {
 			replace = t.handleMangaTitle(match[3])
 		case "title":
 			replace = t.handleChapterTitle(match[3])
+		case "vol":
+			replace = t.handleVolume(match[3])
+		case "group":
+			replace = t.handleGroup(match[3])
+		case "lang":
+			replace = t.handleLanguage(match[3])
+		case "date":
+			replace = t.handleDate(match[3])
 		}
 
 		newString = strings.Replace(newString, match[0], replace, 1)
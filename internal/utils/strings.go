@@ -1,14 +1,15 @@
 package utils
 
 import (
+	"cmp"
 	"strconv"
 	"strings"
 )
 
-// PadFloat formats a float32 with specified total width, preserving original decimals
-func PadFloat(num float32, width int) string {
+// PadFloat formats a float64 with specified total width, preserving original decimals
+func PadFloat(num float64, width int) string {
 	// Convert float to string with full precision
-	str := strconv.FormatFloat(float64(num), 'f', -1, 32)
+	str := strconv.FormatFloat(num, 'f', -1, 64)
 
 	// Split into integer and decimal parts
 	parts := strings.Split(str, ".")
@@ -28,3 +29,47 @@ func PadFloat(num float32, width int) string {
 	}
 	return intPart
 }
+
+// NaturalCompare compares strings like strings.Compare but orders runs of digits by their numeric value, so "2.jpg"
+// comes before "10.jpg"
+func NaturalCompare(a, b string) int {
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if isDigit(a[i]) && isDigit(b[j]) {
+			startA, startB := i, j
+			for i < len(a) && isDigit(a[i]) {
+				i++
+			}
+			for j < len(b) && isDigit(b[j]) {
+				j++
+			}
+
+			numA := strings.TrimLeft(a[startA:i], "0")
+			numB := strings.TrimLeft(b[startB:j], "0")
+			if len(numA) != len(numB) {
+				return cmp.Compare(len(numA), len(numB))
+			}
+			if c := strings.Compare(numA, numB); c != 0 {
+				return c
+			}
+			continue
+		}
+
+		if a[i] != b[j] {
+			return cmp.Compare(a[i], b[j])
+		}
+		i++
+		j++
+	}
+
+	if c := cmp.Compare(len(a)-i, len(b)-j); c != 0 {
+		return c
+	}
+
+	// names only differing in leading zeros still need a stable order
+	return strings.Compare(a, b)
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
@@ -0,0 +1,16 @@
+package utils
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNaturalCompare(t *testing.T) {
+	names := []string{"10.jpg", "page 2.png", "2.jpg", "1.jpg", "page 10.png", "002.jpg", "a.jpg", "page 1.png"}
+	slices.SortFunc(names, NaturalCompare)
+
+	want := []string{"1.jpg", "002.jpg", "2.jpg", "10.jpg", "a.jpg", "page 1.png", "page 2.png", "page 10.png"}
+	if !slices.Equal(names, want) {
+		t.Errorf("sorted = %v, want %v", names, want)
+	}
+}